@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// AWS AppConfig Source
+// =============================================================================
+
+// AppConfigOptions identify an AWS AppConfig configuration profile.
+// Hosted configuration profiles and feature flag profiles are both supported.
+type AppConfigOptions struct {
+	Application     string // application name or ID
+	Environment     string // environment name or ID
+	Profile         string // configuration profile name or ID
+	Region          string // defaults to AWS_REGION / AWS_DEFAULT_REGION
+	MinPollInterval time.Duration
+	Credentials     AWSCredentialsProvider // defaults to DefaultAWSCredentials
+	Auth            AuthProvider           // replaces the default Credentials when set
+	Client          *http.Client
+	Endpoint        string // overrides https://appconfigdata.<region>.amazonaws.com
+}
+
+// AppConfigSource reads configuration through the AppConfig Data API.
+// It keeps a configuration session open and implements Poller, so Watch
+// picks up new deployments at the poll interval AppConfig prescribes.
+type AppConfigSource struct {
+	BaseSource
+	opts AppConfigOptions
+
+	mu       sync.Mutex
+	token    string
+	nextPoll time.Time
+	data     map[string]any
+	fetched  bool
+}
+
+func AppConfig(opts AppConfigOptions) *AppConfigSource {
+	return AppConfigWithPriority(opts, DefaultRemotePriority)
+}
+
+func AppConfigWithPriority(opts AppConfigOptions, priority int) *AppConfigSource {
+	opts.Region = awsRegion(opts.Region)
+	if opts.Credentials == nil && opts.Auth == nil {
+		opts.Credentials = DefaultAWSCredentials()
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://appconfigdata." + opts.Region + ".amazonaws.com"
+	}
+
+	name := fmt.Sprintf("appconfig:%s/%s/%s", opts.Application, opts.Environment, opts.Profile)
+	return &AppConfigSource{
+		BaseSource: NewBaseSource(name, priority),
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *AppConfigSource) Remote() bool { return true }
+
+// Load returns the latest configuration, fetching it on first use.
+func (s *AppConfigSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll fetches the latest configuration once the poll interval has elapsed
+// and reports whether a new version was deployed.
+func (s *AppConfigSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch(context.Background())
+}
+
+// fetch calls GetLatestConfiguration, starting or restarting the session
+// as needed. An empty response body means the configuration is unchanged.
+func (s *AppConfigSource) fetch(ctx context.Context) (bool, error) {
+	if s.token == "" {
+		if err := s.startSession(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	resp, body, err := s.getLatest(ctx)
+	if err == nil && resp.StatusCode == http.StatusBadRequest {
+		// Session tokens expire after 24 hours; start a new session once.
+		if err := s.startSession(ctx); err != nil {
+			return false, err
+		}
+		resp, body, err = s.getLatest(ctx)
+	}
+	if err != nil {
+		return false, fmt.Errorf("appconfig: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("appconfig: get latest configuration: %s: %s", resp.Status, body)
+	}
+
+	s.token = resp.Header.Get("Next-Poll-Configuration-Token")
+	s.nextPoll = time.Now().Add(s.pollInterval(resp.Header.Get("Next-Poll-Interval-In-Seconds")))
+	s.fetched = true
+
+	if len(body) == 0 {
+		return false, nil
+	}
+
+	var decoded map[string]any
+	if err := contentDecoder(resp.Header.Get("Content-Type")).Decode(body, &decoded); err != nil {
+		return false, fmt.Errorf("appconfig: decode configuration: %w", err)
+	}
+	s.data = flattenToDot(decoded)
+	return true, nil
+}
+
+func (s *AppConfigSource) startSession(ctx context.Context) error {
+	req := map[string]any{
+		"ApplicationIdentifier":          s.opts.Application,
+		"EnvironmentIdentifier":          s.opts.Environment,
+		"ConfigurationProfileIdentifier": s.opts.Profile,
+	}
+	if s.opts.MinPollInterval > 0 {
+		req["RequiredMinimumPollIntervalInSeconds"] = int(s.opts.MinPollInterval / time.Second)
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	resp, body, err := doAWSRequest(ctx, s.opts.Client, s.opts.Credentials, http.MethodPost,
+		s.opts.Endpoint+"/configurationsessions", s.opts.Region, "appconfig", payload, header)
+	if err != nil {
+		return fmt.Errorf("appconfig: start session: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("appconfig: start session: %s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		InitialConfigurationToken string
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return fmt.Errorf("appconfig: decode session: %w", err)
+	}
+	s.token = out.InitialConfigurationToken
+	return nil
+}
+
+func (s *AppConfigSource) getLatest(ctx context.Context) (*http.Response, []byte, error) {
+	endpoint := s.opts.Endpoint + "/configuration?configuration_token=" + url.QueryEscape(s.token)
+	return doAWSRequest(ctx, s.opts.Client, s.opts.Credentials, http.MethodGet,
+		endpoint, s.opts.Region, "appconfig", nil, nil)
+}
+
+func (s *AppConfigSource) pollInterval(header string) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if s.opts.MinPollInterval > 0 {
+		return s.opts.MinPollInterval
+	}
+	return time.Minute
+}
+
+// contentDecoder picks a decoder for a MIME type, defaulting to JSON.
+func contentDecoder(contentType string) FileDecoder {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-yaml", "application/yaml", "text/yaml", "text/x-yaml":
+		return yamlDecoder{}
+	default:
+		return jsonDecoder{}
+	}
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Incident Archives
+// =============================================================================
+
+const defaultHistoryLimit = 32
+
+// archiveTimeFormat names bundle directories; it sorts chronologically.
+const archiveTimeFormat = "20060102T150405.000Z"
+
+// ArchiveManifest describes an archive bundle. It is written as
+// manifest.json next to the other bundle files; Hash and Keys describe
+// snapshot.json, after filtering and redaction.
+type ArchiveManifest struct {
+	CreatedAt time.Time       `json:"created_at"`
+	LoadedAt  time.Time       `json:"loaded_at"`
+	Hash      string          `json:"hash"`
+	Keys      int             `json:"keys"`
+	Sources   []ArchiveSource `json:"sources"`
+	Files     []string        `json:"files"`
+}
+
+// ArchiveSource names a registered source and its priority.
+type ArchiveSource struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// archivedChange is a ChangeSet as written to history.json.
+type archivedChange struct {
+	At       time.Time      `json:"at"`
+	Changed  map[string]any `json:"changed"`
+	Previous map[string]any `json:"previous,omitempty"`
+}
+
+// archivedEvent is an Event as written to events.json.
+type archivedEvent struct {
+	Type     EventType       `json:"type"`
+	Time     time.Time       `json:"time"`
+	Source   string          `json:"source,omitempty"`
+	Keys     int             `json:"keys,omitempty"`
+	Duration time.Duration   `json:"duration,omitempty"`
+	Err      string          `json:"error,omitempty"`
+	Changes  *archivedChange `json:"changes,omitempty"`
+}
+
+// archivedReport is a LoadReport with errors rendered as strings.
+type archivedReport struct {
+	StartedAt      time.Time        `json:"started_at"`
+	Duration       time.Duration    `json:"duration"`
+	Sources        []archivedSource `json:"sources"`
+	Hooks          []string         `json:"hooks,omitempty"`
+	Defaults       []string         `json:"defaults,omitempty"`
+	RulesEvaluated int              `json:"rules_evaluated"`
+	Keys           int              `json:"keys"`
+	Changed        int              `json:"changed"`
+	Warnings       []string         `json:"warnings,omitempty"`
+	Err            string           `json:"error,omitempty"`
+}
+
+type archivedSource struct {
+	Name     string        `json:"name"`
+	Priority int           `json:"priority"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Keys     int           `json:"keys"`
+	Err      string        `json:"error,omitempty"`
+}
+
+// Archive freezes the current configuration for incident forensics. It
+// creates a timestamped directory under dir holding the snapshot,
+// provenance, recent change history, recent lifecycle events and the last
+// load report, and returns the directory's path. Secret values are
+// redacted throughout, and filters such as ExcludePII restrict the keys
+// included. Retention is set with WithHistory.
+func (c *Config) Archive(dir string, filters ...ExportFilter) (string, error) {
+	now := time.Now().UTC()
+
+	c.mu.RLock()
+	data := c.export(c.data, filters)
+	provenance := make(map[string]string, len(data))
+	for k := range data {
+		if from, ok := c.provenance[k]; ok {
+			provenance[k] = from
+		}
+	}
+	history := make([]archivedChange, len(c.history))
+	for i := range c.history {
+		history[i] = *c.archiveChange(&c.history[i], filters)
+	}
+	report := c.report
+	manifest := ArchiveManifest{CreatedAt: now, LoadedAt: c.loadedAt, Keys: len(data)}
+	for _, src := range c.sources {
+		manifest.Sources = append(manifest.Sources, ArchiveSource{Name: src.Name(), Priority: src.Priority()})
+	}
+	// Change sets may still be merged by a coalescing window under c.mu.
+	events := c.archiveEvents(c.events.retained(), filters)
+	c.mu.RUnlock()
+	sum := sha256.Sum256(canonicalBytes(data))
+	manifest.Hash = hex.EncodeToString(sum[:])
+
+	files := map[string]any{
+		"snapshot.json":   data,
+		"provenance.json": provenance,
+		"history.json":    history,
+		"events.json":     events,
+	}
+	if report != nil {
+		files["report.json"] = archiveReport(report)
+	}
+	manifest.Files = mapKeys(files)
+	files["manifest.json"] = manifest
+
+	bundle := filepath.Join(dir, "config-archive-"+now.Format(archiveTimeFormat))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("archive: %w", err)
+	}
+	if err := os.Mkdir(bundle, 0o700); err != nil {
+		return "", fmt.Errorf("archive: %w", err)
+	}
+	for name, v := range files {
+		raw, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return bundle, fmt.Errorf("archive %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(bundle, name), append(raw, '\n'), 0o600); err != nil {
+			return bundle, fmt.Errorf("archive %s: %w", name, err)
+		}
+	}
+	return bundle, nil
+}
+
+// ArchiveOnSignal writes an archive to dir each time one of sigs arrives,
+// for example syscall.SIGUSR1, until the returned stop function is called
+// or the configuration is closed. Failures go to the error handler.
+func (c *Config) ArchiveOnSignal(dir string, sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+
+	c.watchers.Add(1)
+	go func() {
+		defer c.watchers.Done()
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				if _, err := c.Archive(dir); err != nil {
+					c.handleError(err)
+				}
+			case <-done:
+				return
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// ArchiveHandler returns an HTTP handler that writes an archive to dir on
+// POST and responds with {"path": "..."}. Mount it on a debug listener.
+func (c *Config) ArchiveHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		path, err := c.Archive(dir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"path": path})
+	})
+}
+
+// History returns the retained change sets, oldest first.
+func (c *Config) History() []ChangeSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]ChangeSet(nil), c.history...)
+}
+
+// recordHistory retains a copy of cs; the caller must hold c.mu.
+func (c *Config) recordHistory(cs *ChangeSet) {
+	if c.historyLimit <= 0 {
+		return
+	}
+	c.history = append(c.history, ChangeSet{Changed: maps.Clone(cs.Changed), Previous: maps.Clone(cs.Previous), At: cs.At})
+	if over := len(c.history) - c.historyLimit; over > 0 {
+		c.history = append(c.history[:0], c.history[over:]...)
+	}
+}
+
+// archiveChange converts cs for history.json; the caller must hold c.mu.
+func (c *Config) archiveChange(cs *ChangeSet, filters []ExportFilter) *archivedChange {
+	ac := &archivedChange{At: cs.At, Changed: c.export(cs.Changed, filters)}
+	if len(cs.Previous) > 0 {
+		ac.Previous = c.export(cs.Previous, filters)
+	}
+	return ac
+}
+
+// archiveEvents converts events for events.json; the caller must hold c.mu.
+func (c *Config) archiveEvents(events []Event, filters []ExportFilter) []archivedEvent {
+	out := make([]archivedEvent, len(events))
+	for i, ev := range events {
+		out[i] = archivedEvent{
+			Type:     ev.Type,
+			Time:     ev.Time,
+			Source:   ev.Source,
+			Keys:     ev.Keys,
+			Duration: ev.Duration,
+			Err:      errString(ev.Err),
+		}
+		if ev.Changes != nil {
+			out[i].Changes = c.archiveChange(ev.Changes, filters)
+		}
+	}
+	return out
+}
+
+func archiveReport(r *LoadReport) archivedReport {
+	out := archivedReport{
+		StartedAt:      r.StartedAt,
+		Duration:       r.Duration,
+		Hooks:          r.Hooks,
+		Defaults:       r.Defaults,
+		RulesEvaluated: r.RulesEvaluated,
+		Keys:           r.Keys,
+		Changed:        r.Changed,
+		Warnings:       r.Warnings,
+		Err:            errString(r.Err),
+	}
+	for _, s := range r.Sources {
+		out.Sources = append(out.Sources, archivedSource{
+			Name:     s.Name,
+			Priority: s.Priority,
+			Status:   s.Status,
+			Duration: s.Duration,
+			Keys:     s.Keys,
+			Err:      errString(s.Err),
+		})
+	}
+	return out
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,569 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Auth Providers
+// =============================================================================
+
+// AuthProvider authenticates an outgoing HTTP request, typically by setting
+// the Authorization header or signing it. Every remote source accepts one
+// in its options' Auth field; when set, it replaces the source's default
+// credentials. Implementations must be safe for concurrent use.
+type AuthProvider interface {
+	Authorize(req *http.Request) error
+}
+
+// AuthProviderFunc adapts a function to AuthProvider.
+type AuthProviderFunc func(req *http.Request) error
+
+func (f AuthProviderFunc) Authorize(req *http.Request) error {
+	return f(req)
+}
+
+// TLSAuthProvider is an AuthProvider that authenticates at the TLS layer,
+// such as with a client certificate. AuthClient applies ConfigureTLS to a
+// copy of the client's transport.
+type TLSAuthProvider interface {
+	AuthProvider
+	ConfigureTLS(cfg *tls.Config) error
+}
+
+// Refresher is implemented by providers that cache tokens. When a request
+// is rejected with 401, AuthClient calls Refresh and retries it once.
+type Refresher interface {
+	Refresh()
+}
+
+// StaticToken sends a fixed bearer token.
+func StaticToken(token string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// BasicAuth sends HTTP basic credentials.
+func BasicAuth(username, password string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// BearerTokenFunc sends the bearer token returned by fn for each request,
+// e.g. BearerTokenFunc(ApplicationDefaultCredentials().Token). fn is
+// expected to cache its tokens.
+func BearerTokenFunc(fn func(ctx context.Context) (string, error)) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		token, err := fn(req.Context())
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// AWSSigV4 signs requests with AWS Signature Version 4 for the given
+// region and service.
+func AWSSigV4(creds AWSCredentialsProvider, region, service string) AuthProvider {
+	if creds == nil {
+		creds = DefaultAWSCredentials()
+	}
+	region = awsRegion(region)
+	return AuthProviderFunc(func(req *http.Request) error {
+		c, err := creds.Credentials(req.Context())
+		if err != nil {
+			return err
+		}
+		body, err := requestBody(req)
+		if err != nil {
+			return err
+		}
+		signAWSRequest(req, body, c, region, service, time.Now())
+		return nil
+	})
+}
+
+// MultiAuth applies several providers in order, e.g. a client certificate
+// together with a bearer token.
+func MultiAuth(providers ...AuthProvider) AuthProvider {
+	return multiAuth(providers)
+}
+
+type multiAuth []AuthProvider
+
+func (m multiAuth) Authorize(req *http.Request) error {
+	for _, p := range m {
+		if err := p.Authorize(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiAuth) ConfigureTLS(cfg *tls.Config) error {
+	for _, p := range m {
+		if t, ok := p.(TLSAuthProvider); ok {
+			if err := t.ConfigureTLS(cfg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m multiAuth) Refresh() {
+	for _, p := range m {
+		if r, ok := p.(Refresher); ok {
+			r.Refresh()
+		}
+	}
+}
+
+// requestBody returns a copy of the request body without consuming it.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("auth: request body cannot be re-read")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// =============================================================================
+// OAuth2 Client Credentials
+// =============================================================================
+
+// OAuth2Options configure the OAuth2 client credentials grant.
+type OAuth2Options struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Params       url.Values   // extra token request parameters, e.g. audience
+	Client       *http.Client // defaults to a client with a 30s timeout
+}
+
+// OAuth2ClientCredentials returns a provider that obtains bearer tokens
+// with the client credentials grant. Tokens are cached and fetched again
+// a minute before they expire, or after the server rejects one.
+func OAuth2ClientCredentials(opts OAuth2Options) AuthProvider {
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	return &oauth2Auth{opts: opts}
+}
+
+type oauth2Auth struct {
+	opts OAuth2Options
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (o *oauth2Auth) Authorize(req *http.Request) error {
+	token, err := o.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns a valid access token, fetching one when needed.
+func (o *oauth2Auth) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != "" && time.Until(o.expires) > time.Minute {
+		return o.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.opts.Scopes, " "))
+	}
+	for k, v := range o.opts.Params {
+		form[k] = v
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.opts.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.opts.ClientID), url.QueryEscape(o.opts.ClientSecret))
+
+	token, expires, err := requestToken(o.opts.Client, req)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: %w", err)
+	}
+	o.token, o.expires = token, expires
+	return o.token, nil
+}
+
+// requestToken sends an OAuth2 token request and returns the bearer token
+// and its expiry, an hour away when the server sets none.
+func requestToken(client *http.Client, req *http.Request) (string, time.Time, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", time.Time{}, fmt.Errorf("decode token: %w", err)
+	}
+	if out.AccessToken == "" {
+		return "", time.Time{}, errors.New("token response has no access_token")
+	}
+	if out.TokenType != "" && !strings.EqualFold(out.TokenType, "bearer") {
+		return "", time.Time{}, fmt.Errorf("unsupported token type %q", out.TokenType)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	if out.ExpiresIn > 0 {
+		expires = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	}
+	return out.AccessToken, expires, nil
+}
+
+// Refresh discards the cached token.
+func (o *oauth2Auth) Refresh() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token = ""
+}
+
+// =============================================================================
+// Bootstrap Token Exchange
+// =============================================================================
+
+// BootstrapOptions configure a bootstrap token exchange.
+type BootstrapOptions struct {
+	// TokenEnv names the variable holding the bootstrap token. It is read
+	// and removed from the environment when the provider is created, so
+	// child processes do not inherit it.
+	TokenEnv string
+	Token    string // used when TokenEnv is empty or unset
+
+	// ExchangeURL receives an RFC 8693 token exchange request with the
+	// bootstrap token as subject_token, and answers with an OAuth2 token
+	// response. Audience and Scopes are sent when set.
+	ExchangeURL string
+	Audience    string
+	Scopes      []string
+	Client      *http.Client // defaults to a client with a 30s timeout
+
+	// Exchange replaces the default exchange request, e.g. for a Vault
+	// response-wrapped token or a cloud-specific login endpoint.
+	Exchange func(ctx context.Context, bootstrap string) (token string, expires time.Time, err error)
+	// Renew obtains a fresh credential from the current one, as the
+	// bootstrap token cannot be used again. Without it the credential is
+	// used until it expires.
+	Renew func(ctx context.Context, token string) (string, time.Time, error)
+}
+
+// BootstrapToken exchanges a short-lived bootstrap token for a long-lived
+// credential on first use, then discards the bootstrap token, overwriting
+// its bytes. The credential is sent as a bearer token and renewed through
+// Renew a minute before it expires or after the server rejects it.
+func BootstrapToken(opts BootstrapOptions) *BootstrapAuth {
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	token := opts.Token
+	if opts.TokenEnv != "" {
+		if v, ok := os.LookupEnv(opts.TokenEnv); ok {
+			token = v
+			os.Unsetenv(opts.TokenEnv)
+		}
+	}
+	opts.Token = ""
+	return &BootstrapAuth{opts: opts, bootstrap: []byte(token)}
+}
+
+// BootstrapAuth is the AuthProvider returned by BootstrapToken.
+type BootstrapAuth struct {
+	opts BootstrapOptions
+
+	mu        sync.Mutex
+	bootstrap []byte // nil once exchanged
+	token     string
+	expires   time.Time
+	stale     bool
+}
+
+func (b *BootstrapAuth) Authorize(req *http.Request) error {
+	token, err := b.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Exchange performs the exchange now rather than on the first request,
+// so startup fails early on a bad bootstrap token. It is a no-op once
+// exchanged.
+func (b *BootstrapAuth) Exchange(ctx context.Context) error {
+	_, err := b.Token(ctx)
+	return err
+}
+
+// Token returns the long-lived credential, exchanging or renewing it when
+// needed.
+func (b *BootstrapAuth) Token(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.token != "" && !b.stale && time.Until(b.expires) > time.Minute {
+		return b.token, nil
+	}
+
+	if b.bootstrap == nil {
+		if b.opts.Renew == nil {
+			if b.token != "" && time.Now().Before(b.expires) {
+				return b.token, nil
+			}
+			return "", errors.New("bootstrap: credential expired and the bootstrap token was already used")
+		}
+		token, expires, err := b.opts.Renew(ctx, b.token)
+		if err != nil {
+			return "", fmt.Errorf("bootstrap: renew: %w", err)
+		}
+		b.token, b.expires, b.stale = token, expires, false
+		return b.token, nil
+	}
+
+	if len(b.bootstrap) == 0 {
+		return "", errors.New("bootstrap: no bootstrap token")
+	}
+	token, expires, err := b.exchange(ctx, string(b.bootstrap))
+	if err != nil {
+		return "", fmt.Errorf("bootstrap: exchange: %w", err)
+	}
+	clear(b.bootstrap)
+	b.bootstrap = nil
+	b.token, b.expires, b.stale = token, expires, false
+	return b.token, nil
+}
+
+// Refresh marks the credential for renewal after the server rejected it.
+func (b *BootstrapAuth) Refresh() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stale = b.opts.Renew != nil
+}
+
+func (b *BootstrapAuth) exchange(ctx context.Context, bootstrap string) (string, time.Time, error) {
+	if b.opts.Exchange != nil {
+		return b.opts.Exchange(ctx, bootstrap)
+	}
+	form := url.Values{
+		"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"subject_token":      {bootstrap},
+		"subject_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+	}
+	if b.opts.Audience != "" {
+		form.Set("audience", b.opts.Audience)
+	}
+	if len(b.opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(b.opts.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.opts.ExchangeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return requestToken(b.opts.Client, req)
+}
+
+// =============================================================================
+// Mutual TLS
+// =============================================================================
+
+// MutualTLS authenticates with a client certificate read from PEM files.
+// The files are re-read when they change, so rotated certificates are
+// picked up by new connections without a restart.
+func MutualTLS(certFile, keyFile string) TLSAuthProvider {
+	return &mtlsAuth{certFile: certFile, keyFile: keyFile}
+}
+
+type mtlsAuth struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (m *mtlsAuth) Authorize(*http.Request) error { return nil }
+
+func (m *mtlsAuth) ConfigureTLS(cfg *tls.Config) error {
+	if _, err := m.certificate(); err != nil {
+		return err
+	}
+	cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return m.certificate()
+	}
+	return nil
+}
+
+// certificate returns the key pair, reloading it if either file changed.
+func (m *mtlsAuth) certificate() (*tls.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var latest time.Time
+	for _, f := range []string{m.certFile, m.keyFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("mtls: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if m.cert != nil && latest.Equal(m.modTime) {
+		return m.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: %w", err)
+	}
+	m.cert, m.modTime = &cert, latest
+	return m.cert, nil
+}
+
+// =============================================================================
+// Authenticated Clients
+// =============================================================================
+
+// AuthClient returns a copy of client whose requests are authorized by
+// auth. TLS providers are applied to a clone of the client's transport,
+// which must then be an *http.Transport. Requests rejected with 401 are
+// retried once after refreshing a Refresher's cached credentials.
+// Clients from NewHTTPClient may be wrapped as well.
+func AuthClient(client *http.Client, auth AuthProvider) *http.Client {
+	if client == nil {
+		client = defaultHTTPClient(30 * time.Second)
+	}
+	out := *client
+	t := &authTransport{base: client.Transport, auth: auth}
+	if t.base == nil {
+		t.base = http.DefaultTransport
+	}
+	if tp, ok := auth.(TLSAuthProvider); ok {
+		t.base, t.err = configureTLS(t.base, tp)
+	}
+	out.Transport = t
+	return &out
+}
+
+func configureTLS(base http.RoundTripper, auth TLSAuthProvider) (http.RoundTripper, error) {
+	if at, ok := base.(*authTransport); ok {
+		inner, err := configureTLS(at.base, auth)
+		if err != nil {
+			return base, err
+		}
+		return &authTransport{base: inner, auth: at.auth, err: at.err}, nil
+	}
+	tr, ok := base.(*http.Transport)
+	if !ok {
+		return base, fmt.Errorf("auth: cannot configure TLS on %T", base)
+	}
+	tr = tr.Clone()
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	if err := auth.ConfigureTLS(tr.TLSClientConfig); err != nil {
+		return base, err
+	}
+	return tr, nil
+}
+
+type authTransport struct {
+	base http.RoundTripper
+	auth AuthProvider
+	err  error // TLS configuration error, reported on every request
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.err != nil {
+		closeBody(req)
+		return nil, t.err
+	}
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	r, ok := t.auth.(Refresher)
+	if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+	r.Refresh()
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.send(retry)
+}
+
+// send authorizes a clone of req, as RoundTrippers must not modify the
+// caller's request.
+func (t *authTransport) send(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if err := t.auth.Authorize(out); err != nil {
+		closeBody(req)
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return t.base.RoundTrip(out)
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// AWS Credentials
+// =============================================================================
+
+// AWSCredentials are the keys used to sign AWS requests.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expires         time.Time // zero for long-lived keys
+}
+
+// AWSCredentialsProvider resolves credentials for signing.
+type AWSCredentialsProvider interface {
+	Credentials(ctx context.Context) (AWSCredentials, error)
+}
+
+// StaticAWSCredentials always returns the same keys.
+type StaticAWSCredentials AWSCredentials
+
+func (s StaticAWSCredentials) Credentials(context.Context) (AWSCredentials, error) {
+	return AWSCredentials(s), nil
+}
+
+// DefaultAWSCredentials resolves credentials like the AWS SDKs, in order:
+// environment variables, the ECS container endpoint, then EC2 instance
+// metadata (IMDSv2). Temporary credentials are cached until shortly before
+// they expire.
+func DefaultAWSCredentials() AWSCredentialsProvider {
+	return &awsCredentialChain{client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+type awsCredentialChain struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	cached AWSCredentials
+}
+
+func (p *awsCredentialChain) Credentials(ctx context.Context) (AWSCredentials, error) {
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		return AWSCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached.AccessKeyID != "" && time.Until(p.cached.Expires) > 5*time.Minute {
+		return p.cached, nil
+	}
+
+	var creds AWSCredentials
+	var err error
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+		creds, err = p.fetch(ctx, "http://169.254.170.2"+rel, nil)
+	} else if full := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); full != "" {
+		creds, err = p.fetch(ctx, full, map[string]string{"Authorization": os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")})
+	} else {
+		creds, err = p.instanceCredentials(ctx)
+	}
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("aws credentials: %w", err)
+	}
+	p.cached = creds
+	return creds, nil
+}
+
+func (p *awsCredentialChain) instanceCredentials(ctx context.Context) (AWSCredentials, error) {
+	const imds = "http://169.254.169.254/latest"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, imds+"/api/token", nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "21600")
+	token, err := p.do(req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("imds token: %w", err)
+	}
+	headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, imds+"/meta-data/iam/security-credentials/", nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+	role, err := p.do(req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("imds role: %w", err)
+	}
+	return p.fetch(ctx, imds+"/meta-data/iam/security-credentials/"+strings.TrimSpace(string(role)), headers)
+}
+
+func (p *awsCredentialChain) fetch(ctx context.Context, endpoint string, headers map[string]string) (AWSCredentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	for k, v := range headers {
+		if v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+	body, err := p.do(req)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+
+	var out struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		Token           string `json:"Token"`
+		Expiration      time.Time
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return AWSCredentials{}, fmt.Errorf("decode credentials: %w", err)
+	}
+	return AWSCredentials{
+		AccessKeyID:     out.AccessKeyID,
+		SecretAccessKey: out.SecretAccessKey,
+		SessionToken:    out.Token,
+		Expires:         out.Expiration,
+	}, nil
+}
+
+func (p *awsCredentialChain) do(req *http.Request) ([]byte, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	return body, nil
+}
+
+// awsRegion returns region, falling back to AWS_REGION and AWS_DEFAULT_REGION.
+func awsRegion(region string) string {
+	if region != "" {
+		return region
+	}
+	if r := os.Getenv("AWS_REGION"); r != "" {
+		return r
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// =============================================================================
+// Signature Version 4
+// =============================================================================
+
+// signAWSRequest signs req in place with AWS Signature Version 4.
+func signAWSRequest(req *http.Request, body []byte, creds AWSCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.URL.Host
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "x-amz-date" || lower == "x-amz-content-sha256" || lower == "x-amz-security-token" ||
+			lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscapePath(req.URL.EscapedPath()),
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func awsEscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, awsQueryEscape(k)+"="+awsQueryEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func awsQueryEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// doAWSRequest signs and sends a request, returning the response with its
+// body fully read.
+func doAWSRequest(ctx context.Context, client *http.Client, creds AWSCredentialsProvider,
+	method, endpoint, region, service string, body []byte, header http.Header,
+) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if creds != nil { // nil when the client carries an AuthProvider
+		c, err := creds.Credentials(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		signAWSRequest(req, body, c, region, service, time.Now())
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp, data, err
+}
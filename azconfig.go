@@ -0,0 +1,323 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Azure App Configuration Source
+// =============================================================================
+
+// AzureNullLabel selects key-values that have no label.
+const AzureNullLabel = "\x00"
+
+// AzureAppConfigOptions configure an Azure App Configuration store.
+type AzureAppConfigOptions struct {
+	Endpoint     string   // e.g. https://myapp.azconfig.io
+	KeyFilter    string   // defaults to "*"
+	KeyPrefix    string   // trimmed from keys, e.g. "myapp:"
+	KeySeparator string   // replaced by ".", defaults to ":"
+	Labels       []string // loaded in order, later labels win; defaults to the null label
+
+	// SentinelKeys are checked on every poll; only when one of them changes
+	// is the whole store reloaded. Without sentinels every poll reloads.
+	SentinelKeys    []string
+	RefreshInterval time.Duration // minimum time between polls, defaults to 30s
+
+	Credentials AzureTokenProvider // defaults to ManagedIdentity("")
+	Auth        AuthProvider       // replaces the default Credentials when set
+	Client      *http.Client
+}
+
+// AzureAppConfigSource reads key-values from Azure App Configuration.
+// JSON values are expanded into nested keys, and feature flags appear
+// under "featureflags.<name>". With FollowProfiles the active profile
+// name is used as an extra label, so profiles map onto store labels.
+type AzureAppConfigSource struct {
+	BaseSource
+	opts     AzureAppConfigOptions
+	profiles *ProfileManager
+
+	mu        sync.Mutex
+	data      map[string]any
+	etags     map[string]string // "key\x00label" -> etag
+	sentinels map[string]string
+	label     string // profile label of the last fetch
+	fetched   bool
+	nextPoll  time.Time
+}
+
+func AzureAppConfig(opts AzureAppConfigOptions) *AzureAppConfigSource {
+	return AzureAppConfigWithPriority(opts, DefaultRemotePriority)
+}
+
+func AzureAppConfigWithPriority(opts AzureAppConfigOptions, priority int) *AzureAppConfigSource {
+	opts.Endpoint = strings.TrimRight(opts.Endpoint, "/")
+	if opts.KeyFilter == "" {
+		opts.KeyFilter = "*"
+	}
+	if opts.KeySeparator == "" {
+		opts.KeySeparator = ":"
+	}
+	if len(opts.Labels) == 0 {
+		opts.Labels = []string{AzureNullLabel}
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 30 * time.Second
+	}
+	if opts.Credentials == nil && opts.Auth == nil {
+		opts.Credentials = ManagedIdentity("")
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+
+	return &AzureAppConfigSource{
+		BaseSource: NewBaseSource("azappconfig:"+opts.Endpoint, priority),
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// FollowProfiles loads the active profile's name as an additional label,
+// applied after the configured labels.
+func (s *AzureAppConfigSource) FollowProfiles(pm *ProfileManager) *AzureAppConfigSource {
+	s.profiles = pm
+	return s
+}
+
+// Remote reports that the source needs the network.
+func (s *AzureAppConfigSource) Remote() bool { return true }
+
+// Load returns the store contents, fetching them on first use or when the
+// active profile has changed.
+func (s *AzureAppConfigSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched || s.label != s.profileLabel() {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether the store changed since the last fetch. It checks the
+// sentinel keys first and reloads everything only when one of them moved.
+func (s *AzureAppConfigSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	ctx := context.Background()
+	if s.fetched && len(s.opts.SentinelKeys) > 0 {
+		moved, err := s.sentinelsMoved(ctx)
+		if err != nil || !moved {
+			return false, err
+		}
+	}
+	return s.fetch(ctx)
+}
+
+func (s *AzureAppConfigSource) profileLabel() string {
+	if s.profiles == nil {
+		return ""
+	}
+	return s.profiles.GetActiveProfile()
+}
+
+func (s *AzureAppConfigSource) labels() []string {
+	labels := s.opts.Labels
+	if label := s.profileLabel(); label != "" {
+		labels = append(labels[:len(labels):len(labels)], label)
+	}
+	return labels
+}
+
+// azureKeyValue is a single App Configuration entry.
+type azureKeyValue struct {
+	Key         string  `json:"key"`
+	Label       *string `json:"label"`
+	Value       string  `json:"value"`
+	ContentType string  `json:"content_type"`
+	ETag        string  `json:"etag"`
+}
+
+// fetch loads every label and reports whether any entry changed.
+func (s *AzureAppConfigSource) fetch(ctx context.Context) (bool, error) {
+	data := make(map[string]any)
+	etags := make(map[string]string)
+
+	for _, label := range s.labels() {
+		items, err := s.list(ctx, label)
+		if err != nil {
+			return false, err
+		}
+		for _, kv := range items {
+			s.apply(data, kv)
+			etags[kv.Key+"\x00"+label] = kv.ETag
+		}
+	}
+
+	if s.sentinels == nil || !s.fetched {
+		if err := s.recordSentinels(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	changed := !s.fetched || !maps.Equal(etags, s.etags)
+	s.data, s.etags = data, etags
+	s.label = s.profileLabel()
+	s.fetched = true
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+	return changed, nil
+}
+
+func (s *AzureAppConfigSource) list(ctx context.Context, label string) ([]azureKeyValue, error) {
+	q := url.Values{
+		"key":         {s.opts.KeyFilter},
+		"label":       {label},
+		"api-version": {"1.0"},
+	}
+	next := s.opts.Endpoint + "/kv?" + q.Encode()
+
+	var items []azureKeyValue
+	for next != "" {
+		resp, body, err := azureRequest(ctx, s.opts.Client, s.opts.Credentials, azureResource(s.opts.Endpoint), next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("azure app configuration: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("azure app configuration: list key-values: %s: %s", resp.Status, body)
+		}
+
+		var page struct {
+			Items    []azureKeyValue `json:"items"`
+			NextLink string          `json:"@nextLink"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("azure app configuration: decode key-values: %w", err)
+		}
+		items = append(items, page.Items...)
+
+		next = ""
+		if page.NextLink != "" {
+			next = s.opts.Endpoint + page.NextLink
+			if strings.HasPrefix(page.NextLink, "http") {
+				next = page.NextLink
+			}
+		}
+	}
+	return items, nil
+}
+
+// apply stores one entry under its dot-separated key.
+func (s *AzureAppConfigSource) apply(data map[string]any, kv azureKeyValue) {
+	const featureFlagPrefix = ".appconfig.featureflag/"
+
+	var key string
+	if name, ok := strings.CutPrefix(kv.Key, featureFlagPrefix); ok {
+		key = "featureflags." + name
+	} else {
+		key = strings.TrimPrefix(kv.Key, s.opts.KeyPrefix)
+		key = strings.ReplaceAll(key, s.opts.KeySeparator, ".")
+	}
+
+	if isJSONContentType(kv.ContentType) {
+		var decoded any
+		if err := decodeJSON([]byte(kv.Value), &decoded); err == nil {
+			for k := range data {
+				if k == key || strings.HasPrefix(k, key+".") {
+					delete(data, k)
+				}
+			}
+			flatten(key, decoded, data)
+			return
+		}
+	}
+	data[key] = kv.Value
+}
+
+func (s *AzureAppConfigSource) recordSentinels(ctx context.Context) error {
+	s.sentinels = make(map[string]string, len(s.opts.SentinelKeys))
+	for _, key := range s.opts.SentinelKeys {
+		etag, _, err := s.sentinel(ctx, key, "")
+		if err != nil {
+			return err
+		}
+		s.sentinels[key] = etag
+	}
+	return nil
+}
+
+func (s *AzureAppConfigSource) sentinelsMoved(ctx context.Context) (bool, error) {
+	moved := false
+	for _, key := range s.opts.SentinelKeys {
+		etag, changed, err := s.sentinel(ctx, key, s.sentinels[key])
+		if err != nil {
+			return false, err
+		}
+		if changed {
+			s.sentinels[key] = etag
+			moved = true
+		}
+	}
+	return moved, nil
+}
+
+// sentinel fetches a key's etag, using If-None-Match against the known one.
+// A missing key has an empty etag.
+func (s *AzureAppConfigSource) sentinel(ctx context.Context, key, known string) (string, bool, error) {
+	label := s.opts.Labels[len(s.opts.Labels)-1]
+	endpoint := s.opts.Endpoint + "/kv/" + url.PathEscape(key) + "?" +
+		url.Values{"label": {label}, "api-version": {"1.0"}}.Encode()
+
+	header := http.Header{}
+	if known != "" {
+		header.Set("If-None-Match", `"`+known+`"`)
+	}
+
+	resp, body, err := azureRequest(ctx, s.opts.Client, s.opts.Credentials, azureResource(s.opts.Endpoint), endpoint, header)
+	if err != nil {
+		return "", false, fmt.Errorf("azure app configuration: sentinel %q: %w", key, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return known, false, nil
+	case http.StatusNotFound:
+		return "", known != "", nil
+	case http.StatusOK:
+		var kv azureKeyValue
+		if err := json.Unmarshal(body, &kv); err != nil {
+			return "", false, fmt.Errorf("azure app configuration: decode sentinel %q: %w", key, err)
+		}
+		return kv.ETag, kv.ETag != known, nil
+	default:
+		return "", false, fmt.Errorf("azure app configuration: sentinel %q: %s: %s", key, resp.Status, body)
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Azure Credentials
+// =============================================================================
+
+// AzureTokenProvider returns bearer tokens for an Azure resource
+// (e.g. "https://vault.azure.net").
+type AzureTokenProvider interface {
+	Token(ctx context.Context, resource string) (string, error)
+}
+
+// AzureTokenFunc adapts a function to AzureTokenProvider.
+type AzureTokenFunc func(ctx context.Context, resource string) (string, error)
+
+func (f AzureTokenFunc) Token(ctx context.Context, resource string) (string, error) {
+	return f(ctx, resource)
+}
+
+// ManagedIdentity returns a provider that obtains tokens from the Azure
+// managed identity endpoint: the App Service / Functions identity endpoint
+// when IDENTITY_ENDPOINT is set, otherwise the VM instance metadata service.
+// clientID selects a user-assigned identity; leave empty for system-assigned.
+// Tokens are cached per resource until shortly before they expire.
+func ManagedIdentity(clientID string) AzureTokenProvider {
+	return &managedIdentity{
+		clientID: clientID,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		tokens:   make(map[string]azureToken),
+	}
+}
+
+type azureToken struct {
+	value   string
+	expires time.Time
+}
+
+type managedIdentity struct {
+	clientID string
+	client   *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]azureToken
+}
+
+func (m *managedIdentity) Token(ctx context.Context, resource string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tok, ok := m.tokens[resource]; ok && time.Until(tok.expires) > 5*time.Minute {
+		return tok.value, nil
+	}
+
+	q := url.Values{"resource": {resource}}
+	if m.clientID != "" {
+		q.Set("client_id", m.clientID)
+	}
+
+	var req *http.Request
+	var err error
+	if endpoint := os.Getenv("IDENTITY_ENDPOINT"); endpoint != "" {
+		q.Set("api-version", "2019-08-01")
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+q.Encode(), nil)
+		if err == nil {
+			req.Header.Set("X-IDENTITY-HEADER", os.Getenv("IDENTITY_HEADER"))
+		}
+	} else {
+		q.Set("api-version", "2018-02-01")
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			"http://169.254.169.254/metadata/identity/oauth2/token?"+q.Encode(), nil)
+		if err == nil {
+			req.Header.Set("Metadata", "true")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("managed identity: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("managed identity: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("managed identity: %s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("managed identity: decode token: %w", err)
+	}
+
+	tok := azureToken{value: out.AccessToken, expires: time.Now().Add(time.Hour)}
+	if secs, err := strconv.ParseInt(out.ExpiresOn, 10, 64); err == nil {
+		tok.expires = time.Unix(secs, 0)
+	}
+	m.tokens[resource] = tok
+	return tok.value, nil
+}
+
+// =============================================================================
+// Azure Requests
+// =============================================================================
+
+// azureRequest issues an authenticated GET against an Azure data-plane API.
+// It returns the response with its body fully read.
+func azureRequest(ctx context.Context, client *http.Client, creds AzureTokenProvider,
+	resource, endpoint string, header http.Header,
+) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if creds != nil { // nil when the client carries an AuthProvider
+		token, err := creds.Token(ctx, resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+// azureResource returns the token audience for a service endpoint.
+func azureResource(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return strings.TrimRight(endpoint, "/")
+	}
+	return u.Scheme + "://" + u.Host
+}
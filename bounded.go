@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// =============================================================================
+// Bounded Calls
+// =============================================================================
+
+// ErrStillRunning is returned instead of starting a script, module or
+// evaluator call while an earlier one that timed out without honouring
+// cancellation has not returned yet.
+var ErrStillRunning = errors.New("previous call still running after timeout")
+
+// boundedCalls runs calls into runtimes that should, but may not, abort
+// when their context is done. A call returns at its deadline; the runtime
+// keeps its goroutine until it actually returns, and until then new calls
+// fail with ErrStillRunning rather than piling up more goroutines.
+type boundedCalls struct {
+	mu        sync.Mutex
+	abandoned int // timed-out calls still running
+}
+
+// callState tracks one call; guarded by boundedCalls.mu.
+type callState struct {
+	finished  bool
+	abandoned bool
+}
+
+func runBounded[T any](b *boundedCalls, ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	b.mu.Lock()
+	if b.abandoned > 0 {
+		b.mu.Unlock()
+		return zero, ErrStillRunning
+	}
+	b.mu.Unlock()
+
+	type result struct {
+		value T
+		err   error
+	}
+	call := &callState{}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn(ctx)
+		b.mu.Lock()
+		call.finished = true
+		if call.abandoned {
+			b.abandoned--
+		}
+		b.mu.Unlock()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+		return r.value, r.err
+	case <-ctx.Done():
+		b.mu.Lock()
+		if !call.finished {
+			call.abandoned = true
+			b.abandoned++
+		}
+		b.mu.Unlock()
+		return zero, ctx.Err()
+	}
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"errors"
+	"maps"
 	"reflect"
 	"time"
 
@@ -17,8 +19,23 @@ type Builder struct {
 	config     *Config
 	factory    *SourceFactory
 	middleware []SourceMiddleware
+	scope      MiddlewareScope
+	pathMeta   map[string]string
+	pending    []*pendingPaths // sources with templated paths
 }
 
+// MiddlewareScope controls where builder middleware is applied for sources
+// that nest other sources (AddComposite, AddConditional).
+type MiddlewareScope int
+
+const (
+	// MiddlewareNested wraps each nested source, so encrypted or templated
+	// values inside composite children are processed before merging.
+	MiddlewareNested MiddlewareScope = iota
+	// MiddlewareOuter wraps the composite or conditional source as a whole.
+	MiddlewareOuter
+)
+
 // NewBuilder creates a new builder with sensible defaults.
 func NewBuilder() *Builder {
 	return &Builder{
@@ -40,7 +57,56 @@ func (b *Builder) WithContext(ctx context.Context) *Builder {
 
 // WithValidator sets a custom validator.
 func (b *Builder) WithValidator(v *validator.Validate) *Builder {
-	b.config.validate = v
+	b.config.setValidator(v)
+	return b
+}
+
+// WithValidationTag overrides the struct tag holding validation rules.
+func (b *Builder) WithValidationTag(tag string) *Builder {
+	WithValidationTag(tag)(b.config)
+	return b
+}
+
+// WithKeyNamespaces reports struct validation errors under config keys.
+func (b *Builder) WithKeyNamespaces() *Builder {
+	WithKeyNamespaces()(b.config)
+	return b
+}
+
+// Offline disables remote sources, their polling and webhooks, serving
+// remote sources from the offline cache when one is set. Useful for
+// air-gapped builds, local development and unit tests.
+func (b *Builder) Offline(enabled bool) *Builder {
+	WithOffline(enabled)(b.config)
+	return b
+}
+
+// OfflineCache keeps copies of remote sources' loads in dir for offline use.
+func (b *Builder) OfflineCache(dir string) *Builder {
+	WithOfflineCache(dir)(b.config)
+	return b
+}
+
+// TwelveFactor makes loading fail unless every key can be overridden by an
+// environment variable.
+func (b *Builder) TwelveFactor() *Builder {
+	WithTwelveFactor()(b.config)
+	return b
+}
+
+// WithLimits rejects loads exceeding limits on key count, value size or
+// nesting depth.
+func (b *Builder) WithLimits(limits Limits) *Builder {
+	WithLimits(limits)(b.config)
+	return b
+}
+
+// WithOptions applies config options, such as the generic WithEnum, which
+// have no builder method.
+func (b *Builder) WithOptions(opts ...Option) *Builder {
+	for _, opt := range opts {
+		opt(b.config)
+	}
 	return b
 }
 
@@ -90,19 +156,63 @@ func (b *Builder) WithRetry(attempts int, backoff time.Duration) *Builder {
 	return b
 }
 
+// WithChaos injects random failures and latency into all sources. Intended
+// for integration tests of retry, fallback, and caching setups.
+func (b *Builder) WithChaos(failureProbability float64, maxLatency time.Duration) *Builder {
+	b.middleware = append(b.middleware, WithChaos(failureProbability, maxLatency))
+	return b
+}
+
+// WithSharedLoading deduplicates loads of identical sources across all
+// configs in the process using DefaultSharedLoader.
+func (b *Builder) WithSharedLoading() *Builder {
+	b.middleware = append(b.middleware, WithSharedLoading(DefaultSharedLoader))
+	return b
+}
+
+// WithMiddlewareScope sets where middleware is applied for composite and
+// conditional sources. The default is MiddlewareNested.
+func (b *Builder) WithMiddlewareScope(scope MiddlewareScope) *Builder {
+	b.scope = scope
+	return b
+}
+
 // =============================================================================
 // Source Management - Generic Add Method
 // =============================================================================
 
 // AddSource adds a generic source with middleware applied.
 func (b *Builder) AddSource(src Source) *Builder {
+	b.config.AddSource(b.wrap(src))
+	return b
+}
+
+// wrap applies the builder middleware to src.
+func (b *Builder) wrap(src Source) Source {
 	if len(b.middleware) > 0 {
 		src = ChainMiddleware(b.middleware...)(src)
 	}
-	b.config.AddSource(src)
+	return src
+}
+
+// addNesting adds a source built around nested ones, applying middleware
+// according to the scope.
+func (b *Builder) addNesting(build func(wrap func(Source) Source) Source) *Builder {
+	if b.scope == MiddlewareOuter {
+		return b.AddSource(build(func(src Source) Source { return src }))
+	}
+	b.config.AddSource(build(b.wrap))
 	return b
 }
 
+func wrapAll(sources []Source, wrap func(Source) Source) []Source {
+	out := make([]Source, len(sources))
+	for i, src := range sources {
+		out[i] = wrap(src)
+	}
+	return out
+}
+
 // AddSourceWithMiddleware adds a source with specific middleware.
 func (b *Builder) AddSourceWithMiddleware(src Source, mw ...SourceMiddleware) *Builder {
 	src = ChainMiddleware(mw...)(src)
@@ -119,9 +229,36 @@ func (b *Builder) AddMemory(data map[string]any) *Builder {
 	return b.AddSource(b.factory.CreateMemorySource(data))
 }
 
-// AddFile adds a file source.
+// AddFile adds a file source. Paths of file-based sources may hold
+// text/template placeholders resolved when the config is built:
+// {{.profile}} is the active profile, {{.meta.name}} a WithPathMeta value
+// and {{env "NAME"}} an environment variable, e.g.
+// AddFile("config.{{.profile | default \"dev\"}}.yaml"). ~ and $VAR are
+// expanded as by ExpandPath. A path that cannot be resolved fails the
+// load: BuildAndLoad returns the error, and after Build the first Load
+// does.
 func (b *Builder) AddFile(path string) *Builder {
-	return b.AddSource(b.factory.CreateFileSource(path))
+	factory := b.factory
+	return b.addPaths([]string{path}, func(p ...string) Source {
+		return factory.CreateFileSource(p[0])
+	})
+}
+
+// AddFileWithPriority adds a file source at priority, overriding any
+// _meta.priority annotation in the file.
+func (b *Builder) AddFileWithPriority(path string, priority int) *Builder {
+	return b.addPaths([]string{path}, func(p ...string) Source {
+		return FileWithPriority(p[0], priority).WithPriority(priority)
+	})
+}
+
+// AddTable adds a file, typically a CSV or TSV lookup table, with its keys
+// nested under prefix.
+func (b *Builder) AddTable(path, prefix string) *Builder {
+	priority := b.factory.defaultPriority
+	return b.addPaths([]string{path}, func(p ...string) Source {
+		return FileWithPriority(p[0], priority).WithPrefix(prefix)
+	})
 }
 
 // AddEnv adds an environment variable source.
@@ -131,7 +268,24 @@ func (b *Builder) AddEnv(prefix string) *Builder {
 
 // AddGlob adds a multi-file source using glob patterns.
 func (b *Builder) AddGlob(pattern string) *Builder {
-	return b.AddSource(b.factory.CreateMultiFileSource(pattern))
+	factory := b.factory
+	return b.addPaths([]string{pattern}, func(p ...string) Source {
+		return factory.CreateMultiFileSource(p[0])
+	})
+}
+
+// AddValues adds Helm-style values files; later files override earlier ones.
+func (b *Builder) AddValues(files ...string) *Builder {
+	return b.addPaths(files, func(p ...string) Source { return Values(p...) })
+}
+
+// AddKVPairs adds --set style pairs ("a.b=1", "c=x") above files and
+// environment variables.
+func (b *Builder) AddKVPairs(pairs ...string) *Builder {
+	if _, err := ParseKVPairs(pairs...); err != nil {
+		panic(err)
+	}
+	return b.AddSource(KVPairs(pairs...))
 }
 
 // AddFiles adds multiple file sources at once.
@@ -148,12 +302,92 @@ func (b *Builder) AddFiles(paths ...string) *Builder {
 
 // AddComposite adds a composite source merging multiple sources.
 func (b *Builder) AddComposite(name string, priority int, sources ...Source) *Builder {
-	return b.AddSource(NewCompositeSource(name, priority, sources...))
+	return b.addNesting(func(wrap func(Source) Source) Source {
+		return NewCompositeSource(name, priority, wrapAll(sources, wrap)...)
+	})
+}
+
+// AddCompositeWithPolicy adds a composite source resolving conflicting
+// keys between its children with policy.
+func (b *Builder) AddCompositeWithPolicy(name string, priority int, policy ConflictPolicy, sources ...Source) *Builder {
+	return b.addNesting(func(wrap func(Source) Source) Source {
+		return NewCompositeSource(name, priority, wrapAll(sources, wrap)...).WithConflictPolicy(policy)
+	})
 }
 
 // AddConditional adds a conditional source.
 func (b *Builder) AddConditional(src Source, condition func() bool) *Builder {
-	return b.AddSource(NewConditionalSource(src, condition))
+	return b.addNesting(func(wrap func(Source) Source) Source {
+		return NewConditionalSource(wrap(src), condition)
+	})
+}
+
+// AddAppConfig adds an AWS AppConfig source.
+func (b *Builder) AddAppConfig(opts AppConfigOptions) *Builder {
+	return b.AddSource(AppConfig(opts))
+}
+
+// AddAzureAppConfig adds an Azure App Configuration source whose labels
+// follow the active profile.
+func (b *Builder) AddAzureAppConfig(opts AzureAppConfigOptions) *Builder {
+	return b.AddSource(AzureAppConfig(opts).FollowProfiles(b.config.EnableProfiles()))
+}
+
+// AddKeyVault adds an Azure Key Vault source. The keys it loads are
+// treated as secret.
+func (b *Builder) AddKeyVault(opts KeyVaultOptions) *Builder {
+	return b.AddSource(KeyVault(opts))
+}
+
+// AddSecretManager adds a Google Secret Manager source. The keys it loads
+// are treated as secret.
+func (b *Builder) AddSecretManager(opts SecretManagerOptions) *Builder {
+	return b.AddSource(SecretManager(opts))
+}
+
+// AddFirestore adds a Firestore document source.
+func (b *Builder) AddFirestore(opts FirestoreOptions) *Builder {
+	return b.AddSource(Firestore(opts))
+}
+
+// AddLDAP adds an LDAP subtree source.
+func (b *Builder) AddLDAP(searcher LDAPSearcher, opts LDAPOptions) *Builder {
+	return b.AddSource(LDAP(searcher, opts))
+}
+
+// AddMongo adds a MongoDB document source.
+func (b *Builder) AddMongo(coll MongoCollection, opts MongoOptions) *Builder {
+	return b.AddSource(Mongo(coll, opts))
+}
+
+// AddJsonnet adds a source evaluating a Jsonnet file.
+func (b *Builder) AddJsonnet(path string, eval Evaluator, opts EvalOptions) *Builder {
+	return b.addPaths([]string{path}, func(p ...string) Source { return Jsonnet(p[0], eval, opts) })
+}
+
+// AddCUE adds a source evaluating a CUE file.
+func (b *Builder) AddCUE(path string, eval Evaluator, opts EvalOptions) *Builder {
+	return b.addPaths([]string{path}, func(p ...string) Source { return CUE(p[0], eval, opts) })
+}
+
+// AddStarlark adds a source running a sandboxed Starlark script.
+func (b *Builder) AddStarlark(path string, runtime ScriptRuntime, opts StarlarkOptions) *Builder {
+	return b.addPaths([]string{path}, func(p ...string) Source { return Starlark(p[0], runtime, opts) })
+}
+
+// AddExec adds a source that runs a helper binary printing JSON.
+func (b *Builder) AddExec(command string, opts ExecOptions) *Builder {
+	return b.AddSource(Exec(command, opts))
+}
+
+// AddObject adds an object storage source (s3://, gs:// or azblob://).
+func (b *Builder) AddObject(rawURL string, opts ObjectOptions) *Builder {
+	return b.AddSource(Object(rawURL, opts))
+}
+
+// AddOCI adds a source pulling a config artifact from an OCI registry.
+func (b *Builder) AddOCI(ref string, opts OCIOptions) *Builder {
+	return b.AddSource(OCI(ref, opts))
 }
 
 // =============================================================================
@@ -161,14 +395,63 @@ func (b *Builder) AddConditional(src Source, condition func() bool) *Builder {
 // =============================================================================
 
 // AddObserver adds an observer for configuration changes.
-func (b *Builder) AddObserver(observer Observer) *Builder {
-	b.config.Observe(observer)
+func (b *Builder) AddObserver(observer Observer, opts ...ObserverOption) *Builder {
+	b.config.Observe(observer, opts...)
+	return b
+}
+
+// AddChangeSetObserver adds an observer receiving full change sets.
+func (b *Builder) AddChangeSetObserver(observer ChangeSetObserver, opts ...ObserverOption) *Builder {
+	b.config.ObserveChanges(observer, opts...)
+	return b
+}
+
+// AddWebhook posts redacted change sets to a webhook after all other
+// observers have run.
+func (b *Builder) AddWebhook(opts WebhookOptions) *Builder {
+	return b.AddChangeSetObserver(NewWebhookNotifier(b.config, opts), WithObserverPriority(1000))
+}
+
+// MarkSecret flags keys matching the patterns as secret.
+func (b *Builder) MarkSecret(patterns ...string) *Builder {
+	b.config.MarkSecret(patterns...)
+	return b
+}
+
+// WatchInterval polls the named source at most every d while watching.
+func (b *Builder) WatchInterval(source string, d time.Duration) *Builder {
+	b.config.SetWatchInterval(source, d)
+	return b
+}
+
+// MarkPII tags keys matching the patterns as personal data.
+func (b *Builder) MarkPII(patterns ...string) *Builder {
+	b.config.MarkPII(patterns...)
+	return b
+}
+
+// RedactWith marks keys matching the patterns as secret and redacts them
+// with r.
+func (b *Builder) RedactWith(r Redactor, patterns ...string) *Builder {
+	b.config.RedactWith(r, patterns...)
 	return b
 }
 
 // AddObserverFunc adds a function observer.
-func (b *Builder) AddObserverFunc(fn func(changed map[string]any)) *Builder {
-	b.config.ObserveFunc(fn)
+func (b *Builder) AddObserverFunc(fn func(changed map[string]any), opts ...ObserverOption) *Builder {
+	b.config.ObserveFunc(fn, opts...)
+	return b
+}
+
+// WithChangeCoalescing merges changes within window into one observer delivery.
+func (b *Builder) WithChangeCoalescing(window time.Duration) *Builder {
+	WithChangeCoalescing(window)(b.config)
+	return b
+}
+
+// OnError sets a handler for asynchronous errors (watch reloads, observers).
+func (b *Builder) OnError(fn func(error)) *Builder {
+	WithErrorHandler(fn)(b.config)
 	return b
 }
 
@@ -197,6 +480,16 @@ func (b *Builder) AddDefaultsHook(defaults map[string]any) *Builder {
 	return b.AddHook(NewDefaultsHook(defaults))
 }
 
+// AddWASMHook adds a hook running a sandboxed WASM module after loading.
+func (b *Builder) AddWASMHook(runtime WASMRuntime, module []byte, opts WASMHookOptions) *Builder {
+	return b.AddHook(NewWASMHook(runtime, module, opts))
+}
+
+// AddRPCHook adds a hook forwarding load events to an external process.
+func (b *Builder) AddRPCHook(transport HookTransport, opts RPCHookOptions) *Builder {
+	return b.AddHook(NewRPCHook(transport, opts))
+}
+
 // =============================================================================
 // Extensions
 // =============================================================================
@@ -258,23 +551,38 @@ func (b *Builder) AddRule(key string, rule string) *Builder {
 }
 
 // AddRules adds multiple validation rules at once.
-func (b *Builder) AddRules(rules ...*validationRules) *Builder {
+func (b *Builder) AddRules(rules ...*Rule) *Builder {
 	b.config.AddRules(rules...)
 	return b
 }
 
+// ApplyRuleSet applies a registered rule set under a key prefix.
+func (b *Builder) ApplyRuleSet(prefix, name string) *Builder {
+	if err := b.config.ApplyRuleSet(prefix, name); err != nil {
+		panic(err)
+	}
+	return b
+}
+
 // =============================================================================
 // Build Methods
 // =============================================================================
 
-// Build creates the final configuration instance without loading.
+// Build creates the final configuration instance without loading. It
+// panics if a source path template is malformed; a path that cannot be
+// resolved, e.g. because it names an unset variable, makes the first Load
+// fail instead.
 func (b *Builder) Build() *Config {
+	var syntax *pathSyntaxError
+	if err := b.resolvePaths(); errors.As(err, &syntax) {
+		panic(err)
+	}
 	return b.config
 }
 
 // MustBuild builds and loads, panicking on error.
 func (b *Builder) MustBuild() *Config {
-	if err := b.config.Load(); err != nil {
+	if err := b.load(); err != nil {
 		panic(err)
 	}
 	return b.config
@@ -282,15 +590,43 @@ func (b *Builder) MustBuild() *Config {
 
 // BuildAndLoad loads the configuration and returns the instance.
 func (b *Builder) BuildAndLoad() (*Config, error) {
-	if err := b.config.Load(); err != nil {
+	if err := b.load(); err != nil {
 		return nil, err
 	}
 	return b.config, nil
 }
 
+// BuildAndLoadWithRetry loads the configuration, retrying the whole load
+// per policy. Use it when sources may be briefly unavailable at startup.
+func (b *Builder) BuildAndLoadWithRetry(ctx context.Context, policy RetryPolicy) (*Config, error) {
+	if err := b.resolvePaths(); err != nil {
+		return nil, err
+	}
+	if err := b.config.LoadWithRetry(ctx, policy); err != nil {
+		return nil, err
+	}
+	return b.config, nil
+}
+
+// BuildAndLoadWithReport loads the configuration and returns it with the
+// load's report. The report is returned even when loading fails.
+func (b *Builder) BuildAndLoadWithReport() (*Config, *LoadReport, error) {
+	if err := b.resolvePaths(); err != nil {
+		return nil, nil, err
+	}
+	report, err := b.config.Reload()
+	if err != nil {
+		return nil, report, err
+	}
+	return b.config, report, nil
+}
+
 // BuildAndWatch loads and starts watching for changes.
 func (b *Builder) BuildAndWatch(interval time.Duration) (*Config, error) {
-	if err := b.config.Load(); err != nil {
+	if err := validateWatchInterval(interval); err != nil {
+		return nil, err
+	}
+	if err := b.load(); err != nil {
 		return nil, err
 	}
 	if err := b.config.Watch(interval); err != nil {
@@ -299,6 +635,26 @@ func (b *Builder) BuildAndWatch(interval time.Duration) (*Config, error) {
 	return b.config, nil
 }
 
+// BuildAndWatchGroup loads the configuration and registers it with a shared
+// WatchGroup instead of starting a dedicated watcher.
+func (b *Builder) BuildAndWatchGroup(group *WatchGroup) (*Config, error) {
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	if err := group.Add(b.config); err != nil {
+		return nil, err
+	}
+	return b.config, nil
+}
+
+// load resolves source paths and loads the configuration.
+func (b *Builder) load() error {
+	if err := b.resolvePaths(); err != nil {
+		return err
+	}
+	return b.config.Load()
+}
+
 // MustBuildAndWatch builds, loads, and watches, panicking on error.
 func (b *Builder) MustBuildAndWatch(interval time.Duration) *Config {
 	config, err := b.BuildAndWatch(interval)
@@ -349,10 +705,34 @@ func (b *Builder) ApplyIf(condition bool, fn func(*Builder) *Builder) *Builder {
 }
 
 // Clone creates a copy of the builder for branching configuration.
+// It is equivalent to CloneShared.
 func (b *Builder) Clone() *Builder {
+	return b.CloneShared()
+}
+
+// CloneShared copies the builder's factory and middleware but keeps the same
+// underlying Config: sources, observers, hooks, and rules added through
+// either builder land in the one shared instance.
+func (b *Builder) CloneShared() *Builder {
 	return &Builder{
 		config:     b.config, // Shared config
 		factory:    NewSourceFactory(b.factory.defaultPriority),
 		middleware: append([]SourceMiddleware{}, b.middleware...),
+		pathMeta:   maps.Clone(b.pathMeta),
+		pending:    b.clonePending(b.config),
+	}
+}
+
+// CloneDetached copies the builder together with an independent Config (see
+// Config.CloneDetached), so both branches can diverge without affecting
+// each other.
+func (b *Builder) CloneDetached() *Builder {
+	config := b.config.CloneDetached()
+	return &Builder{
+		config:     config,
+		factory:    NewSourceFactory(b.factory.defaultPriority),
+		middleware: append([]SourceMiddleware{}, b.middleware...),
+		pathMeta:   maps.Clone(b.pathMeta),
+		pending:    b.clonePending(config),
 	}
 }
@@ -0,0 +1,50 @@
+// Package configtest helps tests run against a tweaked configuration
+// without affecting the configuration other tests share.
+package configtest
+
+import (
+	"maps"
+	"math"
+	"testing"
+
+	config "github.com/os-golib/go-config"
+	"github.com/os-golib/go-config/source"
+)
+
+// OverridePriority is the priority of Scoped overrides, above any other
+// source.
+const OverridePriority = math.MaxInt
+
+// Scoped returns a detached clone of base with overrides layered on top
+// and loaded, failing the test if the load or its validation fails. Keys
+// may be dotted ("server.port") or nested maps. The clone is closed when
+// the test ends, stopping any watchers started on it; base is never
+// modified, so parallel subtests can each scope their own overrides.
+// Observers registered on base are dropped from the clone, so loading it
+// never notifies them; register observers on the returned config instead.
+func Scoped(t testing.TB, base *config.Config, overrides map[string]any) *config.Config {
+	t.Helper()
+	c := base.CloneDetached().RemoveObservers()
+	c.AddSource(&overrideSource{
+		Base: source.NewBase("configtest", OverridePriority),
+		data: config.Flatten(overrides),
+	})
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Errorf("configtest: close scoped config: %v", err)
+		}
+	})
+	if err := c.Load(); err != nil {
+		t.Fatalf("configtest: load scoped config: %v", err)
+	}
+	return c
+}
+
+type overrideSource struct {
+	source.Base
+	data map[string]any
+}
+
+func (s *overrideSource) Load() (map[string]any, error) {
+	return maps.Clone(s.data), nil
+}
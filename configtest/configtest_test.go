@@ -0,0 +1,76 @@
+package configtest_test
+
+import (
+	"testing"
+	"time"
+
+	config "github.com/os-golib/go-config"
+	"github.com/os-golib/go-config/configtest"
+)
+
+func newBase(t *testing.T) *config.Config {
+	t.Helper()
+	base := config.New()
+	base.AddSource(config.Memory(map[string]any{
+		"server.port": 80,
+		"server.host": "localhost",
+	}))
+	if err := base.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestScopedOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]any
+	}{
+		{"dotted", map[string]any{"server.port": 91}},
+		{"nested", map[string]any{"server": map[string]any{"port": 91}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newBase(t)
+			cfg := configtest.Scoped(t, base, tt.overrides)
+
+			if got := cfg.GetInt("server.port"); got != 91 {
+				t.Errorf("scoped server.port = %d, want 91", got)
+			}
+			if got := cfg.GetString("server.host"); got != "localhost" {
+				t.Errorf("scoped server.host = %q, want %q", got, "localhost")
+			}
+			if got := base.GetInt("server.port"); got != 80 {
+				t.Errorf("base server.port = %d, want 80", got)
+			}
+		})
+	}
+}
+
+func TestScopedDropsBaseObservers(t *testing.T) {
+	base := newBase(t)
+	notified := make(chan map[string]any, 2)
+	base.ObserveFunc(func(changed map[string]any) { notified <- changed })
+
+	cfg := configtest.Scoped(t, base, map[string]any{"server.port": 91})
+
+	// Change sets are delivered in order, so once the scoped observer sees
+	// this reload, any delivery of the scoped load has happened too.
+	reloaded := make(chan struct{})
+	cfg.ObserveFunc(func(map[string]any) { close(reloaded) })
+	cfg.AddSource(config.Memory(map[string]any{"server.debug": true}))
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("scoped observer not notified")
+	}
+
+	select {
+	case changed := <-notified:
+		t.Fatalf("base observer notified of scoped load: %v", changed)
+	default:
+	}
+}
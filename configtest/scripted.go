@@ -0,0 +1,106 @@
+package configtest
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/os-golib/go-config/source"
+)
+
+// =============================================================================
+// Scripted Sources
+// =============================================================================
+
+// Step is one result of a ScriptedSource: data, or an error failing the
+// load.
+type Step struct {
+	Data map[string]any
+	Err  error
+}
+
+// Data returns a step loading data.
+func Data(data map[string]any) Step { return Step{Data: data} }
+
+// Fail returns a step failing the load with err.
+func Fail(err error) Step { return Step{Err: err} }
+
+// ScriptedSource returns its steps on successive loads, then repeats the
+// last one. It is a poller reporting a change while steps remain, so with
+// config.ManualWatch every Tick reloads with the next step:
+//
+//	src := configtest.Scripted("api",
+//		configtest.Data(map[string]any{"timeout": "1s"}),
+//		configtest.Fail(errors.New("backend down")),
+//		configtest.Data(map[string]any{"timeout": "2s"}),
+//	)
+//	cfg.AddSource(src)
+//	cfg.Load()                        // timeout=1s
+//	trigger, _ := cfg.ManualWatch()
+//	_, err := trigger.Tick()          // err: backend down, timeout still 1s
+//	_, err = trigger.Tick()           // timeout=2s
+type ScriptedSource struct {
+	source.Base
+	mu    sync.Mutex
+	steps []Step
+	next  int
+	loads int
+}
+
+// Scripted returns a source replaying steps, with memory source priority.
+func Scripted(name string, steps ...Step) *ScriptedSource {
+	return ScriptedWithPriority(name, source.DefaultMemoryPriority, steps...)
+}
+
+func ScriptedWithPriority(name string, priority int, steps ...Step) *ScriptedSource {
+	return &ScriptedSource{
+		Base:  source.NewBase(name, priority),
+		steps: steps,
+	}
+}
+
+// Load returns the next step, or the last one once the script is done. A
+// source without steps loads nothing.
+func (s *ScriptedSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	if len(s.steps) == 0 {
+		return map[string]any{}, nil
+	}
+	step := s.steps[min(s.next, len(s.steps)-1)]
+	if s.next < len(s.steps) {
+		s.next++
+	}
+	if step.Err != nil {
+		return nil, step.Err
+	}
+	return maps.Clone(step.Data), nil
+}
+
+// Poll reports a change while steps remain.
+func (s *ScriptedSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next < len(s.steps), nil
+}
+
+// Append adds steps to the end of the script.
+func (s *ScriptedSource) Append(steps ...Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, steps...)
+}
+
+// Loads returns how many times Load was called.
+func (s *ScriptedSource) Loads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loads
+}
+
+// Remaining returns how many steps have not been loaded yet.
+func (s *ScriptedSource) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.steps) - s.next
+}
@@ -0,0 +1,63 @@
+package configtest_test
+
+import (
+	"errors"
+	"testing"
+
+	config "github.com/os-golib/go-config"
+	"github.com/os-golib/go-config/configtest"
+)
+
+func TestScriptedFailureThenRecovery(t *testing.T) {
+	errDown := errors.New("backend down")
+	src := configtest.Scripted("api",
+		configtest.Data(map[string]any{"timeout": "1s"}),
+		configtest.Fail(errDown),
+		configtest.Data(map[string]any{"timeout": "2s"}),
+	)
+	cfg := config.New()
+	cfg.AddSource(src)
+	t.Cleanup(func() { cfg.Close() })
+
+	var seen []string
+	cfg.ObserveFunc(func(changed map[string]any) {
+		if v, ok := changed["timeout"]; ok {
+			seen = append(seen, v.(string))
+		}
+	})
+	if err := cfg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	trigger, err := cfg.ManualWatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := trigger.Tick()
+	if !reloaded || !errors.Is(err, errDown) {
+		t.Fatalf("failing Tick = %v, %v; want true, %v", reloaded, err, errDown)
+	}
+	if got := cfg.GetString("timeout"); got != "1s" {
+		t.Errorf("timeout after failed reload = %q, want %q", got, "1s")
+	}
+
+	reloaded, err = trigger.Tick()
+	if !reloaded || err != nil {
+		t.Fatalf("recovering Tick = %v, %v; want true, nil", reloaded, err)
+	}
+	if got := cfg.GetString("timeout"); got != "2s" {
+		t.Errorf("timeout after recovery = %q, want %q", got, "2s")
+	}
+
+	reloaded, err = trigger.Tick()
+	if reloaded || err != nil {
+		t.Fatalf("Tick after script end = %v, %v; want false, nil", reloaded, err)
+	}
+	if src.Loads() != 3 || src.Remaining() != 0 {
+		t.Errorf("Loads, Remaining = %d, %d; want 3, 0", src.Loads(), src.Remaining())
+	}
+	if len(seen) != 2 || seen[0] != "1s" || seen[1] != "2s" {
+		t.Errorf("observed timeouts = %v, want [1s 2s]", seen)
+	}
+}
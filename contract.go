@@ -0,0 +1,312 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/os-golib/go-config/rules"
+)
+
+// =============================================================================
+// Config Contracts
+// =============================================================================
+
+// Contract types used in ContractKey.Type.
+const (
+	ContractString   = "string"
+	ContractInt      = "int"
+	ContractUint     = "uint"
+	ContractFloat    = "float"
+	ContractBool     = "bool"
+	ContractDuration = "duration"
+	ContractList     = "list"
+	ContractURL      = "url"
+	ContractMap      = "map"
+)
+
+// ContractKey describes what a service expects of one key (or key pattern).
+type ContractKey struct {
+	Key      string `json:"key"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Contract is a service's exported configuration contract: the keys it
+// reads, their types, and constraints. Contracts are plain JSON so platform
+// teams can collect them and verify a shared values artifact before rollout.
+type Contract struct {
+	Service string        `json:"service"`
+	Keys    []ContractKey `json:"keys"`
+}
+
+// ExportContract builds a contract from the rules registered on c. Use
+// AddStruct to add key types from the structs the service binds.
+func (c *Config) ExportContract(service string) *Contract {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ct := &Contract{Service: service}
+	for key, rule := range c.validationRules {
+		ct.merge(ContractKey{
+			Key:      key,
+			Required: strings.Contains(rule.String(), TagRequired),
+			Rule:     rule.String(),
+			Message:  rule.CustomMessage(),
+		})
+	}
+	ct.sort()
+	return ct
+}
+
+// AddStruct adds every field of a struct (bound under prefix) to the
+// contract, with types from the Go fields and rules from validate tags.
+func (ct *Contract) AddStruct(prefix string, schema any) *Contract {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Struct {
+		ct.addFields(prefix, t)
+	}
+	ct.sort()
+	return ct
+}
+
+func (ct *Contract) addFields(prefix string, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := configKeyName(sf)
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		key := joinKeys(prefix, name)
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		typ := contractType(ft)
+		if typ == "" {
+			ct.addFields(key, ft) // nested struct
+			continue
+		}
+		rule := sf.Tag.Get("validate")
+		ct.merge(ContractKey{
+			Key:      key,
+			Type:     typ,
+			Required: strings.Contains(rule, TagRequired),
+			Rule:     rule,
+		})
+	}
+}
+
+// merge adds a key, combining it with an existing entry for the same key.
+func (ct *Contract) merge(k ContractKey) {
+	for i := range ct.Keys {
+		e := &ct.Keys[i]
+		if e.Key != k.Key {
+			continue
+		}
+		if e.Type == "" {
+			e.Type = k.Type
+		}
+		if e.Rule == "" {
+			e.Rule = k.Rule
+		}
+		if e.Message == "" {
+			e.Message = k.Message
+		}
+		e.Required = e.Required || k.Required
+		return
+	}
+	ct.Keys = append(ct.Keys, k)
+}
+
+func (ct *Contract) sort() {
+	sort.Slice(ct.Keys, func(i, j int) bool { return ct.Keys[i].Key < ct.Keys[j].Key })
+}
+
+// WriteFile writes the contract as indented JSON.
+func (ct *Contract) WriteFile(path string) error {
+	raw, err := json.MarshalIndent(ct, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// LoadContract reads a contract written by WriteFile.
+func LoadContract(path string) (*Contract, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read contract: %w", err)
+	}
+	var ct Contract
+	if err := json.Unmarshal(raw, &ct); err != nil {
+		return nil, fmt.Errorf("decode contract %s: %w", path, err)
+	}
+	return &ct, nil
+}
+
+// contractType maps a Go type to a contract type; "" means nested struct.
+func contractType(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return ContractDuration
+	case reflect.TypeOf(url.URL{}):
+		return ContractURL
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return ""
+	case reflect.Bool:
+		return ContractBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ContractInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ContractUint
+	case reflect.Float32, reflect.Float64:
+		return ContractFloat
+	case reflect.Slice, reflect.Array:
+		return ContractList
+	case reflect.Map:
+		return ContractMap
+	default:
+		return ContractString
+	}
+}
+
+// =============================================================================
+// Contract Verification
+// =============================================================================
+
+// ContractViolation is a single unmet expectation.
+type ContractViolation struct {
+	Service string
+	Key     string
+	Problem string
+}
+
+// ContractViolations is returned by VerifyContracts.
+type ContractViolations struct {
+	Violations []ContractViolation
+}
+
+func (e ContractViolations) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s: %s: %s", v.Service, v.Key, v.Problem)
+	}
+	return "config contract violations: " + strings.Join(parts, "; ")
+}
+
+// VerifyContracts checks that a loaded config artifact satisfies every
+// contract: required keys exist, values have the declared types, and rules
+// pass. Key patterns in contracts are expanded as for Rules.
+func VerifyContracts(artifact *Config, contracts ...*Contract) error {
+	artifact.mu.RLock()
+	data := cloneMap(artifact.data)
+	artifact.mu.RUnlock()
+	keys := mapKeys(data)
+
+	var violations []ContractViolation
+	for _, ct := range contracts {
+		for _, ck := range ct.Keys {
+			targets := []string{ck.Key}
+			if isKeyPattern(ck.Key) {
+				targets = expandKeyPattern(ck.Key, keys)
+			}
+			for _, key := range targets {
+				if problem := artifact.checkContractKey(ck, key, data); problem != "" {
+					violations = append(violations, ContractViolation{Service: ct.Service, Key: key, Problem: problem})
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return ContractViolations{Violations: violations}
+	}
+	return nil
+}
+
+func (c *Config) checkContractKey(ck ContractKey, key string, data map[string]any) string {
+	value, exists := data[key]
+	exists = exists && value != nil // null counts as missing
+	if !exists {
+		if ck.Type == ContractMap || ck.Type == ContractList {
+			exists = hasKeyPrefix(data, key)
+		}
+		if !exists {
+			if ck.Required {
+				return "is required"
+			}
+			return ""
+		}
+	}
+	if value != nil && !matchesContractType(ck.Type, value) {
+		return fmt.Sprintf("must be of type %s, got %T", ck.Type, value)
+	}
+	if ck.Rule != "" && value != nil {
+		rule := rules.New(key).Add(ck.Rule, "").Message(ck.Message)
+		if rule.Dives() {
+			value = listValue(data, key, value)
+		}
+		if err := c.checkRule(rule, value, true); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+func hasKeyPrefix(data map[string]any, prefix string) bool {
+	for k := range data {
+		if strings.HasPrefix(k, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesContractType accepts native values and strings that parse as the
+// declared type, since env and flag sources deliver strings.
+func matchesContractType(typ string, v any) bool {
+	s := fmt.Sprint(v)
+	switch typ {
+	case ContractInt:
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	case ContractUint:
+		_, err := strconv.ParseUint(s, 10, 64)
+		return err == nil
+	case ContractFloat:
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	case ContractBool:
+		_, err := strconv.ParseBool(s)
+		return err == nil
+	case ContractDuration:
+		if _, ok := v.(time.Duration); ok {
+			return true
+		}
+		_, err := time.ParseDuration(s)
+		return err == nil
+	case ContractURL:
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case ContractMap:
+		_, ok := v.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
@@ -54,6 +54,21 @@ func (r *TypeConverterRegistry) registerDefaults() {
 	r.RegisterTypeConverter(reflect.TypeOf(url.URL{}), convertURL)
 }
 
+// clone returns a registry with the same converters.
+func (r *TypeConverterRegistry) clone() *TypeConverterRegistry {
+	out := &TypeConverterRegistry{
+		kindConverters: make(map[reflect.Kind]TypeConverter, len(r.kindConverters)),
+		typeConverters: make(map[reflect.Type]TypeConverter, len(r.typeConverters)),
+	}
+	for k, v := range r.kindConverters {
+		out.kindConverters[k] = v
+	}
+	for k, v := range r.typeConverters {
+		out.typeConverters[k] = v
+	}
+	return out
+}
+
 // RegisterKindConverter registers a converter for a reflect.Kind.
 func (r *TypeConverterRegistry) RegisterKindConverter(kind reflect.Kind, converter TypeConverter) {
 	r.kindConverters[kind] = converter
@@ -1,15 +1,27 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
 	"os"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/os-golib/go-config/rules"
 )
 
 // =============================================================================
@@ -20,10 +32,37 @@ import (
 type Config struct {
 	mu              sync.RWMutex
 	sources         []Source
+	inherited       []Source // shared with the config this was cloned from; left open by Close
 	data            map[string]any
+	provenance      map[string]string
+	suppliers       map[string]Source    // top-level source that supplied each key
+	secretKeys      map[string]bool      // keys reported by SecretKeySources in the latest load
+	changedAt       map[string]time.Time // when each key last changed
+	lastChanged     map[string]any       // keys changed by the latest load
 	validate        *validator.Validate
-	validationRules map[string]string
-	observers       []Observer
+	validationTag   string
+	keyNamespaces   bool
+	validationRules map[string]*Rule
+	observers       []*observerEntry
+	delivery        deliveryQueue
+	onError         func(error)
+	coalesce        coalescer
+	loadedAt        time.Time
+	report          *LoadReport
+	history         []ChangeSet
+	historyLimit    int
+	secretPatterns  []string
+	redactors       []redactionRule
+	redactor        Redactor
+	piiPatterns     []string
+	pii             piiAudit
+	events          eventBus
+	watchers        sync.WaitGroup
+	watchIntervals  map[string]time.Duration
+	offline         bool
+	offlineCache    string
+	twelveFactor    bool
+	limits          Limits
 	ctx             context.Context
 	cancel          context.CancelFunc
 
@@ -53,14 +92,17 @@ func New(opts ...Option) *Config {
 		data:            make(map[string]any),
 		sources:         make([]Source, 0),
 		validate:        validator.New(validator.WithRequiredStructEnabled()),
-		validationRules: make(map[string]string),
-		observers:       make([]Observer, 0),
+		validationTag:   "validate",
+		validationRules: make(map[string]*Rule),
+		observers:       make([]*observerEntry, 0),
 		ctx:             ctx,
 		cancel:          cancel,
 		converter:       NewTypeConverterRegistry(),
 		template:        NewTemplateProcessor(),
 		hooks:           NewHookManager(),
+		historyLimit:    defaultHistoryLimit,
 	}
+	c.events.retain = defaultRetainedEvents
 
 	for _, opt := range opts {
 		opt(c)
@@ -75,83 +117,201 @@ func New(opts ...Option) *Config {
 
 // AddRule adds a validation rule for a specific configuration key.
 func (c *Config) AddRule(key string, rule string) *Config {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.validationRules[key] = rule
-	return c
+	return c.AddRules(rules.New(key).Add(rule, ""))
 }
 
 // AddRules adds multiple validation rules at once.
-func (c *Config) AddRules(rules ...*validationRules) *Config {
+func (c *Config) AddRules(set ...*Rule) *Config {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	for _, rule := range rules {
-		c.validationRules[rule.Key()] = rule.String()
+	for _, rule := range set {
+		c.validationRules[rule.Key()] = rule
 	}
 	return c
 }
 
-// ValidateKey validates a specific key against its registered rules.
+// ApplyRuleSet applies a registered rule set with every key nested under prefix.
+func (c *Config) ApplyRuleSet(prefix, name string) error {
+	set, err := rules.LookupSet(prefix, name)
+	if err != nil {
+		return err
+	}
+	c.AddRules(set...)
+	return nil
+}
+
+// ValidateKey validates a specific key against its registered rules: the
+// rule registered for the key itself, then every wildcard rule matching it
+// in pattern order, as ValidateAll does. All failures are returned joined.
 func (c *Config) ValidateKey(key string) error {
 	c.mu.RLock()
-	rule, exists := c.validationRules[key]
+	var matched []*Rule
+	if rule, ok := c.validationRules[key]; ok {
+		matched = append(matched, rule)
+	}
+	var patterns []string
+	for pattern := range c.validationRules {
+		if isKeyPattern(pattern) && matchKeyPattern(pattern, key) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		matched = append(matched, c.validationRules[pattern])
+	}
 	value, hasValue := c.data[key]
-	c.mu.RUnlock()
-
-	if !exists {
-		return nil // No rule registered
+	hasValue = hasValue && value != nil
+	values := make([]any, len(matched))
+	for i, rule := range matched {
+		values[i] = value
+		if rule.Dives() {
+			values[i] = listValue(c.data, key, value)
+		}
 	}
+	c.mu.RUnlock()
 
-	if !hasValue {
-		if strings.Contains(rule, "required") {
-			return fmt.Errorf("key %q is required but not found", key)
+	var errs []error
+	missing := false
+	for i, rule := range matched {
+		if !hasValue && rule.CustomMessage() == "" && strings.Contains(rule.String(), TagRequired) {
+			if !missing {
+				errs = append(errs, fmt.Errorf("key %q is required but not found", key))
+				missing = true
+			}
+			continue
+		}
+		if err := c.checkRule(rule, values[i], hasValue); err != nil {
+			errs = append(errs, err)
 		}
-		return nil
 	}
-
-	// Create a temporary struct to validate
-	return c.validateValue(key, value, rule)
+	return errors.Join(errs...)
 }
 
 // ValidateAll validates all keys that have registered rules.
+// Wildcard rules are expanded against the currently loaded keys; a key
+// matched by several rules reports all their failures, in pattern order.
 func (c *Config) ValidateAll() error {
+	_, err := c.validateAll()
+	return err
+}
+
+// validateAll validates like ValidateAll and reports how many keys were
+// checked.
+func (c *Config) validateAll() (int, error) {
 	c.mu.RLock()
-	rules := make(map[string]string, len(c.validationRules))
+	rules := make(map[string]*Rule, len(c.validationRules))
 	for k, v := range c.validationRules {
 		rules[k] = v
 	}
 	data := cloneMap(c.data)
 	c.mu.RUnlock()
 
+	patterns := slices.Sorted(maps.Keys(rules))
+
+	var keys []string
+	evaluated := 0
 	errors := make(map[string]string)
-	for key, rule := range rules {
-		value, exists := data[key]
-		if !exists {
-			if strings.Contains(rule, "required") {
-				errors[key] = "is required"
+	for _, pattern := range patterns {
+		rule := rules[pattern]
+		targets := []string{pattern}
+		if isKeyPattern(pattern) {
+			if keys == nil {
+				keys = mapKeys(data)
 			}
-			continue
+			targets = expandKeyPattern(pattern, keys)
 		}
 
-		if err := c.validateValue(key, value, rule); err != nil {
-			errors[key] = err.Error()
+		for _, key := range targets {
+			evaluated++
+			value, exists := data[key]
+			if rule.Dives() {
+				value = listValue(data, key, value)
+			}
+			if err := c.checkRule(rule, value, exists); err != nil {
+				if prev, ok := errors[key]; ok {
+					errors[key] = prev + "; " + err.Error()
+				} else {
+					errors[key] = err.Error()
+				}
+			}
 		}
 	}
 
 	if len(errors) > 0 {
-		return ValidationErrors{Errors: errors}
+		return evaluated, ValidationErrors{Errors: errors}
 	}
-	return nil
+	return evaluated, nil
+}
+
+// checkRule evaluates a rule against a value, applying its custom message.
+// A null value counts as missing.
+func (c *Config) checkRule(rule *Rule, value any, exists bool) error {
+	exists = exists && value != nil
+	var err error
+	switch {
+	case !exists && strings.Contains(rule.String(), TagRequired):
+		err = fmt.Errorf("is required")
+	case !exists:
+		return nil
+	default:
+		err = c.validateValue(rule.Key(), value, rule.String())
+	}
+
+	if err != nil && rule.CustomMessage() != "" {
+		return fmt.Errorf("%s", rule.CustomMessage())
+	}
+	return err
+}
+
+// listValue returns the elements of a list for rules that dive into them:
+// the value itself if it is a list, the typed key.N entries flattened
+// sources keep alongside the joined value, or the value split on commas
+// like GetStringSlice.
+func listValue(data map[string]any, key string, value any) any {
+	var elems []any
+	switch v := value.(type) {
+	case nil, map[string]any:
+		return value
+	case []any:
+		elems = v
+	default:
+		for i := 0; ; i++ {
+			e, ok := data[key+"."+strconv.Itoa(i)]
+			if !ok {
+				break
+			}
+			elems = append(elems, e)
+		}
+		if elems != nil {
+			break
+		}
+		strs, ok := asStringSlice(value)
+		if !ok {
+			elems = []any{value} // a scalar is a list of one
+			break
+		}
+		for _, s := range strs {
+			if s = strings.TrimSpace(s); s != "" {
+				elems = append(elems, s)
+			}
+		}
+	}
+	out := make([]any, len(elems))
+	for i, e := range elems {
+		out[i] = asNumber(e)
+	}
+	return out
 }
 
 // validateValue validates a single value against a rule string.
 func (c *Config) validateValue(_ string, value any, rule string) error {
+	value = asNumber(value)
 	fieldName := "Value"
 	structType := reflect.StructOf([]reflect.StructField{
 		{
 			Name: fieldName,
 			Type: reflect.TypeOf(value),
-			Tag:  reflect.StructTag(fmt.Sprintf(`validate:"%q"`, rule)),
+			Tag:  reflect.StructTag(fmt.Sprintf(`%s:%q`, c.validationTag, rule)),
 		},
 	})
 
@@ -161,6 +321,9 @@ func (c *Config) validateValue(_ string, value any, rule string) error {
 	if err := c.validate.Struct(structValue.Interface()); err != nil {
 		if ve, ok := err.(validator.ValidationErrors); ok {
 			for _, fe := range ve {
+				if i := strings.IndexByte(fe.Field(), '['); i >= 0 {
+					return fmt.Errorf("element %s %s", fe.Field()[i:], validationMessage(fe))
+				}
 				return fmt.Errorf("%s", validationMessage(fe))
 			}
 		}
@@ -174,40 +337,144 @@ func (c *Config) validateValue(_ string, value any, rule string) error {
 // =============================================================================
 
 // Load loads all sources, merges data, and notifies observers of changes.
-func (c *Config) Load() error {
+// Its LoadReport is available from LastLoadReport.
+func (c *Config) Load() (err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	started := time.Now()
+	report := &LoadReport{StartedAt: started}
+	defer func() {
+		report.Err = err
+		report.Duration = time.Since(started)
+		c.report = report
+	}()
+	c.events.emit(Event{Type: EventLoadStarted})
+
 	// Pre-load hook
-	if err := c.hooks.ExecutePreLoad(c); err != nil {
-		return fmt.Errorf("pre-load hook: %w", err)
+	if err := c.hooks.executePreLoad(c, report); err != nil {
+		return c.loadFailed("", fmt.Errorf("pre-load hook: %w", err))
 	}
 
-	merged := make(map[string]any)
+	type loadedSource struct {
+		src  Source
+		data map[string]any
+	}
+	loaded := make([]loadedSource, 0, len(c.sources))
+	secretKeys := make(map[string]bool)
 
 	for _, src := range c.sources {
-		data, err := src.Load()
+		srcStarted := time.Now()
+		data, status, err := c.loadSource(src, report)
+		sr := SourceReport{Name: src.Name(), Priority: src.Priority(), Status: status, Duration: time.Since(srcStarted), Keys: len(data)}
 		if err != nil {
-			return fmt.Errorf("source %s: %w", src.Name(), err)
+			sr.Status, sr.Err = SourceFailed, err
+			report.Sources = append(report.Sources, sr)
+			return c.loadFailed(src.Name(), fmt.Errorf("source %s: %w", src.Name(), err))
+		}
+		if err := c.limits.check(data); err != nil {
+			sr.Status, sr.Err = SourceFailed, err
+			report.Sources = append(report.Sources, sr)
+			return c.loadFailed(src.Name(), fmt.Errorf("source %s: %w", src.Name(), err))
+		}
+		data, applied, err := migrate(src.Name(), data)
+		if err != nil {
+			sr.Status, sr.Err = SourceFailed, err
+			report.Sources = append(report.Sources, sr)
+			return c.loadFailed(src.Name(), fmt.Errorf("source %s: %w", src.Name(), err))
+		}
+		report.Migrations = append(report.Migrations, applied...)
+		sr.Conflicts = sourceConflicts(src)
+		report.Sources = append(report.Sources, sr)
+		for _, cf := range sr.Conflicts {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %q set by %s, kept %s", src.Name(), cf.Key, strings.Join(cf.Sources, ", "), cf.Winner))
+		}
+		if len(data) == 0 && status != SourceOffline {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("source %s returned no keys", src.Name()))
+		}
+		c.events.emit(Event{
+			Type:     EventSourceLoaded,
+			Source:   src.Name(),
+			Keys:     len(data),
+			Duration: sr.Duration,
+		})
+		loaded = append(loaded, loadedSource{src, data})
+		for _, k := range loadedSecretKeys(src, data) {
+			secretKeys[k] = true
+		}
+	}
+
+	// Loading may change priorities (file _meta annotations), so merge in
+	// the resulting order and keep it for the next load.
+	sort.SliceStable(loaded, func(i, j int) bool {
+		return loaded[i].src.Priority() < loaded[j].src.Priority()
+	})
+	merged := make(map[string]any)
+	provenance := make(map[string]string)
+	suppliers := make(map[string]Source)
+	for i, l := range loaded {
+		c.sources[i] = l.src
+		deepMerge(merged, l.data)
+		for k := range l.data {
+			provenance[k] = l.src.Name()
+			suppliers[k] = l.src
 		}
-		deepMerge(merged, data)
 	}
 
 	// Post-load hook
-	if err := c.hooks.ExecutePostLoad(c, merged); err != nil {
-		return fmt.Errorf("post-load hook: %w", err)
+	if err := c.hooks.executePostLoad(c, merged, provenance, report); err != nil {
+		return c.loadFailed("", fmt.Errorf("post-load hook: %w", err))
+	}
+	for k, from := range provenance {
+		if from == "hook:defaults" {
+			report.Defaults = append(report.Defaults, k)
+		}
+	}
+	sort.Strings(report.Defaults)
+	report.Keys = len(merged)
+	if err := c.limits.check(merged); err != nil {
+		return c.loadFailed("", fmt.Errorf("merged config: %w", err))
+	}
+	if c.twelveFactor {
+		if err := envOverrideError(c.envOverrides(nil, merged, provenance, suppliers)); err != nil {
+			return c.loadFailed("", err)
+		}
 	}
 
 	changed := detectChanges(c.data, merged)
+	report.Changed = len(changed)
+	c.loadedAt = time.Now()
+	previous := make(map[string]any, len(changed))
+	for k := range changed {
+		if old, ok := c.data[k]; ok {
+			previous[k] = old
+		}
+	}
 	c.data = merged
+	c.provenance = provenance
+	c.suppliers = suppliers
+	c.secretKeys = secretKeys
+	c.lastChanged = changed
+	if c.changedAt == nil {
+		c.changedAt = make(map[string]time.Time, len(changed))
+	}
+	for k := range changed {
+		c.changedAt[k] = c.loadedAt
+	}
 
 	if len(changed) > 0 {
-		c.notifyObservers(changed)
+		cs := &ChangeSet{Changed: changed, Previous: previous, At: time.Now()}
+		c.events.emit(Event{Type: EventChangesApplied, Keys: len(changed), Changes: cs})
+		c.recordHistory(cs)
+		c.publishChanges(cs)
 	}
 
 	c.mu.Unlock()
 	if len(c.validationRules) > 0 {
-		if err := c.ValidateAll(); err != nil {
+		evaluated, err := c.validateAll()
+		report.RulesEvaluated = evaluated
+		c.events.emit(Event{Type: EventValidated, Err: err, Duration: time.Since(started)})
+		if err != nil {
 			c.mu.Lock()
 			return fmt.Errorf("validation failed: %w", err)
 		}
@@ -217,21 +484,58 @@ func (c *Config) Load() error {
 	return nil
 }
 
-// Watch starts monitoring sources for changes and auto-reloads.
+// loadFailed emits a LoadFailed event and returns err.
+func (c *Config) loadFailed(source string, err error) error {
+	c.events.emit(Event{Type: EventLoadFailed, Source: source, Err: err})
+	return err
+}
+
+// Watch starts monitoring sources for changes and auto-reloads. The
+// interval must lie within [MinWatchInterval, MaxWatchInterval]; pollers
+// may be slowed down further with SetWatchInterval.
 func (c *Config) Watch(interval time.Duration) error {
-	paths := c.collectWatchPaths()
-	if len(paths) == 0 {
-		return fmt.Errorf("no watchable sources configured")
+	if err := validateWatchInterval(interval); err != nil {
+		return err
+	}
+	state, err := c.newWatchState()
+	if err != nil {
+		return err
 	}
 
-	go c.watchLoop(interval, paths)
+	c.watchers.Add(1)
+	go c.watchLoop(interval, state)
 	return nil
 }
 
-// Close stops watching and releases resources.
+// Close stops watching and releases resources. Event subscriptions are
+// closed once running watchers have stopped, then every source (or wrapped
+// source) and hook implementing io.Closer is closed. Sources a clone shares
+// with the config it was cloned from are left open.
 func (c *Config) Close() error {
 	c.cancel()
-	return nil
+	c.coalesce.stop()
+	c.watchers.Wait()
+	c.events.close()
+
+	c.mu.RLock()
+	sources := append([]Source(nil), c.sources...)
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, src := range sources {
+		if slices.ContainsFunc(c.inherited, func(s Source) bool { return sameSource(s, src) }) {
+			continue
+		}
+		walkSources(src, func(s Source) {
+			if closer, ok := s.(io.Closer); ok {
+				errs = append(errs, closer.Close())
+			}
+		})
+	}
+	for _, closer := range c.hooks.closers() {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
 }
 
 // =============================================================================
@@ -256,6 +560,28 @@ func (c *Config) AddSourceWithMiddleware(src Source, middleware ...SourceMiddlew
 	return c.AddSource(src)
 }
 
+// ReplaceSource swaps the source registered under name for src, re-sorting by
+// priority. Observers, hooks, and rules are untouched. If no source has that
+// name, src is added.
+func (c *Config) ReplaceSource(name string, src Source) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	replaced := false
+	for i, existing := range c.sources {
+		if existing.Name() == name {
+			c.sources[i] = src
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		c.sources = append(c.sources, src)
+	}
+	c.sortSources()
+	return c
+}
+
 // RemoveSource removes a source by name.
 func (c *Config) RemoveSource(name string) *Config {
 	c.mu.Lock()
@@ -271,6 +597,78 @@ func (c *Config) RemoveSource(name string) *Config {
 	return c
 }
 
+// =============================================================================
+// Cloning
+// =============================================================================
+
+// CloneDetached returns an independent copy of the configuration. The clone
+// gets its own data, rules, observers, hooks, converters, template functions,
+// and profiles, plus a fresh context, so changes to either side (including
+// Close) do not affect the other. Source instances and the validator are
+// shared, as both are treated as immutable once registered.
+func (c *Config) CloneDetached() *Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clone := &Config{
+		sources:         append([]Source(nil), c.sources...),
+		inherited:       append([]Source(nil), c.sources...),
+		data:            cloneMap(c.data),
+		provenance:      maps.Clone(c.provenance),
+		suppliers:       maps.Clone(c.suppliers),
+		secretKeys:      maps.Clone(c.secretKeys),
+		changedAt:       maps.Clone(c.changedAt),
+		lastChanged:     maps.Clone(c.lastChanged),
+		validate:        c.validate,
+		validationTag:   c.validationTag,
+		keyNamespaces:   c.keyNamespaces,
+		validationRules: make(map[string]*Rule, len(c.validationRules)),
+		onError:         c.onError,
+		secretPatterns:  append([]string(nil), c.secretPatterns...),
+		redactors:       append([]redactionRule(nil), c.redactors...),
+		redactor:        c.redactor,
+		piiPatterns:     append([]string(nil), c.piiPatterns...),
+		watchIntervals:  maps.Clone(c.watchIntervals),
+		ctx:             ctx,
+		cancel:          cancel,
+		converter:       c.converter.clone(),
+		template:        c.template.Clone(),
+		encryption:      c.encryption,
+		hooks:           c.hooks.clone(),
+	}
+	for k, v := range c.validationRules {
+		clone.validationRules[k] = v
+	}
+	clone.observers = make([]*observerEntry, len(c.observers))
+	for i, e := range c.observers {
+		clone.observers[i] = e.cloneFor(clone)
+	}
+	clone.coalesce.window = c.coalesce.window
+	clone.historyLimit = c.historyLimit
+	clone.offline, clone.offlineCache = c.offline, c.offlineCache
+	clone.twelveFactor = c.twelveFactor
+	clone.limits = c.limits
+	clone.events.retain = c.events.retain
+	if c.profiles != nil {
+		clone.profiles = c.profiles.cloneFor(clone)
+	}
+	return clone
+}
+
+// sameSource reports whether a and b are the same source. Sources are
+// usually pointers; values of types that are not comparable are compared
+// deeply.
+func sameSource(a, b Source) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
 // =============================================================================
 // Data Access
 // =============================================================================
@@ -284,12 +682,16 @@ func (c *Config) Get(key string) (any, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	val, ok := c.data[key]
+	if ok {
+		c.auditRead(key)
+	}
 	return val, ok
 }
 
 // getTyped is a generic helper that reduces duplication in Get* methods.
+// Null values are treated as missing.
 func getTyped[T any](c *Config, key string, defaultVal []T, converter func(any) (T, bool)) T {
-	if val, ok := c.Get(key); ok {
+	if val, ok := c.Get(key); ok && val != nil {
 		if converted, ok := converter(val); ok {
 			return converted
 		}
@@ -303,83 +705,247 @@ func getTyped[T any](c *Config, key string, defaultVal []T, converter func(any)
 
 // GetString retrieves a string value with optional default.
 func (c *Config) GetString(key string, defaultVal ...string) string {
-	return getTyped(c, key, defaultVal, func(v any) (string, bool) {
-		if s, ok := v.(string); ok {
-			return s, true
-		}
-		return fmt.Sprint(v), true
-	})
+	return getTyped(c, key, defaultVal, asString)
 }
 
 // GetInt retrieves an integer value with optional default.
 func (c *Config) GetInt(key string, defaultVal ...int) int {
-	return getTyped(c, key, defaultVal, func(v any) (int, bool) {
-		if i, ok := v.(int); ok {
-			return i, true
-		}
-		var result int
-		_, err := fmt.Sscanf(fmt.Sprint(v), "%d", &result)
-		return result, err == nil
-	})
+	return getTyped(c, key, defaultVal, asInt)
 }
 
 // GetBool retrieves a boolean value with optional default.
 func (c *Config) GetBool(key string, defaultVal ...bool) bool {
-	return getTyped(c, key, defaultVal, func(v any) (bool, bool) {
-		if b, ok := v.(bool); ok {
-			return b, true
-		}
-		s := fmt.Sprint(v)
-		return s == "true" || s == "1" || s == "yes", true
-	})
+	return getTyped(c, key, defaultVal, asBool)
 }
 
 // GetDuration retrieves a duration value with optional default.
 func (c *Config) GetDuration(key string, defaultVal ...time.Duration) time.Duration {
-	return getTyped(c, key, defaultVal, func(v any) (time.Duration, bool) {
-		if d, ok := v.(time.Duration); ok {
-			return d, true
-		}
-		if s := fmt.Sprint(v); s != "" {
-			if d, err := time.ParseDuration(s); err == nil {
-				return d, true
-			}
-		}
-		return 0, false
-	})
+	return getTyped(c, key, defaultVal, asDuration)
 }
 
 // GetFloat retrieves a float64 value with optional default.
 func (c *Config) GetFloat(key string, defaultVal ...float64) float64 {
-	return getTyped(c, key, defaultVal, func(v any) (float64, bool) {
-		if f, ok := v.(float64); ok {
-			return f, true
-		}
-		var result float64
-		_, err := fmt.Sscanf(fmt.Sprint(v), "%f", &result)
-		return result, err == nil
-	})
+	return getTyped(c, key, defaultVal, asFloat)
 }
 
 // GetStringSlice retrieves a string slice value with optional default.
 func (c *Config) GetStringSlice(key string, defaultVal ...[]string) []string {
-	return getTyped(c, key, defaultVal, func(v any) ([]string, bool) {
-		switch val := v.(type) {
-		case []string:
-			return val, true
-		case string:
-			return strings.Split(val, ","), true
-		case []any:
-			result := make([]string, len(val))
-			for i, item := range val {
-				result[i] = fmt.Sprint(item)
-			}
-			return result, true
+	return getTyped(c, key, defaultVal, asStringSlice)
+}
+
+// ErrKeyNotFound is returned by the Get*E accessors for missing keys.
+var ErrKeyNotFound = errors.New("config key not found")
+
+// ErrNullValue is returned by the Get*E accessors for keys explicitly set to
+// null. It wraps ErrKeyNotFound, so callers that only care about presence
+// can check for that.
+var ErrNullValue = fmt.Errorf("%w: value is null", ErrKeyNotFound)
+
+// ConversionError is returned by the Get*E accessors when a key is present
+// but its value does not convert to the requested type.
+type ConversionError struct {
+	Key   string
+	Value any
+	Type  string
+	Err   error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("config key %q: cannot convert %v (%T) to %s: %v", e.Key, e.Value, e.Value, e.Type, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error { return e.Err }
+
+// getStrict is the error-returning counterpart of getTyped.
+func getStrict[T any](c *Config, key, typ string, parse func(any) (T, error)) (T, error) {
+	var zero T
+	val, ok := c.Get(key)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+	if val == nil {
+		return zero, fmt.Errorf("%w: %q", ErrNullValue, key)
+	}
+	out, err := parse(val)
+	if err != nil {
+		return zero, &ConversionError{Key: key, Value: val, Type: typ, Err: err}
+	}
+	return out, nil
+}
+
+// GetStringE retrieves a string value, failing with ErrKeyNotFound when the
+// key is missing.
+func (c *Config) GetStringE(key string) (string, error) {
+	return getStrict(c, key, "string", func(v any) (string, error) {
+		s, _ := asString(v)
+		return s, nil
+	})
+}
+
+// GetIntE retrieves an integer value. Unlike GetInt it rejects trailing
+// garbage ("12abc") and fractional numbers, returning a *ConversionError.
+func (c *Config) GetIntE(key string) (int, error) {
+	return getStrict(c, key, "int", parseInt)
+}
+
+// GetBoolE retrieves a boolean value. Unlike GetBool, which treats anything
+// unrecognized as false, it accepts only true/false, 1/0, yes/no and on/off.
+func (c *Config) GetBoolE(key string) (bool, error) {
+	return getStrict(c, key, "bool", parseBool)
+}
+
+// GetDurationE retrieves a duration value.
+func (c *Config) GetDurationE(key string) (time.Duration, error) {
+	return getStrict(c, key, "duration", parseDuration)
+}
+
+// GetFloatE retrieves a float64 value, rejecting trailing garbage.
+func (c *Config) GetFloatE(key string) (float64, error) {
+	return getStrict(c, key, "float", parseFloat)
+}
+
+// GetStringSliceE retrieves a string slice value.
+func (c *Config) GetStringSliceE(key string) ([]string, error) {
+	return getStrict(c, key, "[]string", func(v any) ([]string, error) {
+		if out, ok := asStringSlice(v); ok {
+			return out, nil
 		}
-		return nil, false
+		return nil, errors.New("not a list")
 	})
 }
 
+// Typed conversions shared by the Get* accessors and Value.
+
+func asString(v any) (string, bool) {
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	return fmt.Sprint(v), true
+}
+
+func asInt(v any) (int, bool) {
+	switch x := v.(type) {
+	case int:
+		return x, true
+	case json.Number:
+		i, err := strconv.ParseInt(string(x), 10, 0)
+		return int(i), err == nil
+	}
+	var result int
+	_, err := fmt.Sscanf(fmt.Sprint(v), "%d", &result)
+	return result, err == nil
+}
+
+func asBool(v any) (bool, bool) {
+	if b, ok := v.(bool); ok {
+		return b, true
+	}
+	s := fmt.Sprint(v)
+	return s == "true" || s == "1" || s == "yes", true
+}
+
+func asDuration(v any) (time.Duration, bool) {
+	if d, ok := v.(time.Duration); ok {
+		return d, true
+	}
+	if s := fmt.Sprint(v); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func asFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	var result float64
+	_, err := fmt.Sscanf(fmt.Sprint(v), "%f", &result)
+	return result, err == nil
+}
+
+func asStringSlice(v any) ([]string, bool) {
+	switch val := v.(type) {
+	case []string:
+		return val, true
+	case string:
+		return strings.Split(val, ","), true
+	case []any:
+		result := make([]string, len(val))
+		for i, item := range val {
+			result[i] = fmt.Sprint(item)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// Strict conversions used by the Get*E accessors.
+
+func parseInt(v any) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case float64:
+		if x != math.Trunc(x) {
+			return 0, errors.New("not an integer")
+		}
+		return int(x), nil
+	case json.Number:
+		i, err := strconv.ParseInt(string(x), 10, 0)
+		return int(i), err
+	}
+	return strconv.Atoi(strings.TrimSpace(fmt.Sprint(v)))
+}
+
+func parseBool(v any) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(fmt.Sprint(v))) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, errors.New("not a boolean")
+}
+
+func parseDuration(v any) (time.Duration, error) {
+	if d, ok := v.(time.Duration); ok {
+		return d, nil
+	}
+	return time.ParseDuration(strings.TrimSpace(fmt.Sprint(v)))
+}
+
+func parseFloat(v any) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	}
+	return strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(v)), 64)
+}
+
+// IsNull reports whether key is present with an explicit null value, as
+// from a YAML "~" or JSON null. Typed getters, rules and contracts treat such
+// keys as missing; binding sets pointer, map, slice and interface fields to
+// nil.
+func (c *Config) IsNull(key string) bool {
+	val, ok := c.Get(key)
+	return ok && val == nil
+}
+
 // MustGet panics if the key doesn't exist.
 func (c *Config) MustGet(key string) any {
 	val, ok := c.Get(key)
@@ -394,6 +960,30 @@ func (c *Config) Set(key string, value any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.data[key] = value
+	if c.provenance == nil {
+		c.provenance = make(map[string]string)
+	}
+	c.provenance[key] = SetProvenance
+	delete(c.suppliers, key)
+	if c.changedAt == nil {
+		c.changedAt = make(map[string]time.Time)
+	}
+	c.changedAt[key] = time.Now()
+}
+
+// LastLoaded returns when the configuration was last loaded successfully.
+func (c *Config) LastLoaded() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loadedAt
+}
+
+// Hash returns a stable SHA-256 fingerprint of the current configuration data.
+func (c *Config) Hash() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sum := sha256.Sum256(canonicalBytes(c.data))
+	return hex.EncodeToString(sum[:])
 }
 
 // AllKeys returns all configuration keys.
@@ -416,11 +1006,32 @@ func (c *Config) AllKeys() []string {
 func (c *Config) Bind(dst any) error {
 	c.mu.RLock()
 	data := cloneMap(c.data)
+	c.auditBind(data, "")
 	c.mu.RUnlock()
 
 	return c.bindMapToStruct(data, dst)
 }
 
+// BindPrefix binds the keys under prefix to dst, whose fields map to the
+// sub-keys: BindPrefix("database", &db) sets db.Host from "database.host".
+// Keys outside the prefix are ignored, so component structs need no
+// enclosing mirror of the whole tree. dst may also point to a map, whose
+// entries are keyed by the next segment: BindPrefix("databases", &dbs)
+// with a map[string]Database sets dbs["primary"].Host from
+// "databases.primary.host".
+func (c *Config) BindPrefix(prefix string, dst any) error {
+	c.mu.RLock()
+	data := bindSubtree(c.data, prefix)
+	c.auditBind(data, prefix)
+	c.mu.RUnlock()
+
+	err := c.bindMapToStruct(data, dst)
+	if err != nil && prefix != "" {
+		err = fmt.Errorf("%s: %w", prefix, err)
+	}
+	return err
+}
+
 func (c *Config) BindAndValidate(dst any) error {
 	if err := c.Bind(dst); err != nil {
 		return err
@@ -442,7 +1053,26 @@ func (c *Config) BindWithRules(dst any) error {
 // Validate validates a struct using the configured validator.
 func (c *Config) Validate(dst any) error {
 	if err := c.validate.Struct(dst); err != nil {
-		return wrapValidationError(err)
+		return c.wrapValidationError(err, "")
+	}
+	return nil
+}
+
+// ValidateAs binds the subtree under prefix to a fresh T, exactly as
+// BindPrefix would, and validates it without keeping the bound value. An
+// empty prefix validates the whole tree.
+func ValidateAs[T any](c *Config, prefix string) error {
+	c.mu.RLock()
+	data := bindSubtree(c.data, prefix)
+	c.auditBind(data, prefix)
+	c.mu.RUnlock()
+
+	dst := new(T)
+	if err := c.bindMapToStruct(data, dst); err != nil {
+		return err
+	}
+	if err := c.validate.Struct(dst); err != nil {
+		return c.wrapValidationError(err, prefix)
 	}
 	return nil
 }
@@ -451,17 +1081,28 @@ func (c *Config) Validate(dst any) error {
 // Observation
 // =============================================================================
 
-// Observe registers an observer for configuration changes.
-func (c *Config) Observe(obs Observer) *Config {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.observers = append(c.observers, obs)
-	return c
+// Observe registers an observer for configuration changes. Observers are
+// notified sequentially in priority order (lower first), one change set at a time.
+func (c *Config) Observe(obs Observer, opts ...ObserverOption) *Config {
+	if cso, ok := obs.(ChangeSetObserver); ok {
+		return c.addObserver(&observerEntry{changes: cso}, opts)
+	}
+	return c.addObserver(&observerEntry{observer: obs}, opts)
 }
 
 // ObserveFunc registers a function as an observer.
-func (c *Config) ObserveFunc(fn func(changed map[string]any)) *Config {
-	return c.Observe(ObserverFunc(fn))
+func (c *Config) ObserveFunc(fn func(changed map[string]any), opts ...ObserverOption) *Config {
+	return c.Observe(ObserverFunc(fn), opts...)
+}
+
+// RemoveObservers unregisters every observer, e.g. those a detached clone
+// carried over from its original. Change sets already queued are still
+// delivered.
+func (c *Config) RemoveObservers() *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observers = make([]*observerEntry, 0)
+	return c
 }
 
 // =============================================================================
@@ -513,60 +1154,26 @@ func (c *Config) sortSources() {
 	}
 }
 
-func (c *Config) notifyObservers(changed map[string]any) {
-	for _, obs := range c.observers {
-		go obs.OnConfigChange(cloneMap(changed))
-	}
-}
-
-func (c *Config) collectWatchPaths() []string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	var paths []string
-	for _, src := range c.sources {
-		paths = append(paths, src.WatchPaths()...)
-	}
-	return paths
-}
-
-func (c *Config) watchLoop(interval time.Duration, paths []string) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	modTimes := make(map[string]time.Time)
-	for _, path := range paths {
-		if info, err := os.Stat(path); err == nil {
-			modTimes[path] = info.ModTime()
-		}
-	}
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			if c.hasChanges(modTimes) {
-				_ = c.Load() // Errors logged via hooks
-			}
-		}
+// notifyObservers queues a change set for ordered delivery to the currently
+// registered observers; the caller must hold c.mu.
+func (c *Config) notifyObservers(cs *ChangeSet) {
+	if len(c.observers) == 0 {
+		return
 	}
+	c.delivery.enqueue(cs, append([]*observerEntry(nil), c.observers...))
 }
 
-func (c *Config) hasChanges(modTimes map[string]time.Time) bool {
-	for path, oldTime := range modTimes {
-		info, err := os.Stat(path)
-		if err != nil {
-			continue
-		}
-		if info.ModTime().After(oldTime) {
-			modTimes[path] = info.ModTime()
-			return true
-		}
+// handleError reports an asynchronous error (watch reloads, observers) to the
+// configured error handler. Nil errors are ignored.
+func (c *Config) handleError(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
 	}
-	return false
 }
 
+// bindMapToStruct binds data into the struct or string-keyed map dst points
+// to. For a map, the first segment of each key names the entry, as for map
+// fields.
 func (c *Config) bindMapToStruct(data map[string]any, dst any) error {
 	rv := reflect.ValueOf(dst)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
@@ -574,8 +1181,11 @@ func (c *Config) bindMapToStruct(data map[string]any, dst any) error {
 	}
 
 	rv = rv.Elem()
-	if rv.Kind() != reflect.Struct {
-		return fmt.Errorf("destination must point to a struct")
+	switch {
+	case rv.Kind() == reflect.Struct:
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+	default:
+		return fmt.Errorf("destination must point to a struct or a map with string keys")
 	}
 
 	for key, val := range data {
@@ -588,6 +1198,17 @@ func (c *Config) bindMapToStruct(data map[string]any, dst any) error {
 	return nil
 }
 
+// setNull binds an explicit null: nilable fields are cleared, others keep
+// their current value.
+func setNull(field reflect.Value) {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if field.CanSet() {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
+
 func (c *Config) setByPath(v reflect.Value, path []string, raw any) error {
 	if len(path) == 0 {
 		return nil
@@ -595,7 +1216,11 @@ func (c *Config) setByPath(v reflect.Value, path []string, raw any) error {
 
 	v = indirect(v)
 
-	if v.Kind() != reflect.Struct {
+	switch v.Kind() {
+	case reflect.Map:
+		return c.setMapEntry(v, path, raw)
+	case reflect.Struct:
+	default:
 		return nil
 	}
 
@@ -605,12 +1230,95 @@ func (c *Config) setByPath(v reflect.Value, path []string, raw any) error {
 	}
 
 	if len(path) == 1 {
-		return c.converter.Convert(field, raw)
+		if raw == nil {
+			setNull(field)
+			return nil
+		}
+		return c.setValue(field, raw)
 	}
 
 	return c.setByPath(field, path[1:], raw)
 }
 
+// setMapEntry binds raw into map v under path, whose first segment is the
+// map key: "databases.primary.host" sets Host of the "primary" entry of a
+// map[string]Database field. Entries are created as needed.
+func (c *Config) setMapEntry(v reflect.Value, path []string, raw any) error {
+	t := v.Type()
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("cannot bind into %s: map keys must be strings", t)
+	}
+	if v.IsNil() {
+		if !v.CanSet() {
+			return nil
+		}
+		v.Set(reflect.MakeMap(t))
+	}
+
+	key := reflect.ValueOf(path[0]).Convert(t.Key())
+	if len(path) == 1 && raw == nil {
+		v.SetMapIndex(key, reflect.Value{})
+		return nil
+	}
+	elem := reflect.New(t.Elem()).Elem()
+	if cur := v.MapIndex(key); cur.IsValid() {
+		elem.Set(cur)
+	}
+
+	var err error
+	switch {
+	case len(path) == 1:
+		err = c.setValue(elem, raw)
+	case elem.Kind() == reflect.Interface:
+		// Free-form entries (map[string]any) keep deeper keys as nested maps.
+		m, _ := elem.Interface().(map[string]any)
+		if m == nil {
+			m = make(map[string]any)
+		}
+		setNestedValue(m, path[1:], raw)
+		elem.Set(reflect.ValueOf(m))
+	default:
+		err = c.setByPath(elem, path[1:], raw)
+	}
+	if err != nil {
+		return err
+	}
+	v.SetMapIndex(key, elem)
+	return nil
+}
+
+// setValue converts raw into dst. A nested map bound to a map field is
+// bound entry by entry, so struct entries get the full binder.
+func (c *Config) setValue(dst reflect.Value, raw any) error {
+	m, ok := raw.(map[string]any)
+	if !ok || indirectType(dst.Type()).Kind() != reflect.Map || reflect.TypeOf(raw).AssignableTo(dst.Type()) {
+		return c.converter.Convert(dst, raw)
+	}
+	if _, custom := c.converter.typeConverters[indirectType(dst.Type())]; custom {
+		return c.converter.Convert(dst, raw)
+	}
+	for k, val := range flattenToDot(m) {
+		if err := c.setByPath(dst, splitPath(k), val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setNestedValue sets a dotted path in nested maps, replacing non-map
+// values in the way.
+func setNestedValue(m map[string]any, path []string, v any) {
+	for _, seg := range path[:len(path)-1] {
+		next, ok := m[seg].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[seg] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
 // =============================================================================
 // Options Pattern
 // =============================================================================
@@ -625,7 +1333,67 @@ func WithContext(ctx context.Context) Option {
 
 func WithValidator(v *validator.Validate) Option {
 	return func(c *Config) {
-		c.validate = v
+		c.setValidator(v)
+	}
+}
+
+// WithErrorHandler sets a handler for errors that occur outside a caller's
+// control flow, such as failed watch reloads or bound observer failures.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *Config) {
+		c.onError = fn
+	}
+}
+
+// WithChangeCoalescing merges changes applied within window into a single
+// ChangeSet delivery, so near-simultaneous source updates notify observers once.
+func WithChangeCoalescing(window time.Duration) Option {
+	return func(c *Config) {
+		c.coalesce.window = window
+	}
+}
+
+// WithHistory sets how many applied change sets and lifecycle events are
+// retained for Archive (defaults 32 and 128). Zero disables retention.
+func WithHistory(changes, events int) Option {
+	return func(c *Config) {
+		c.historyLimit = max(changes, 0)
+		c.events.retain = max(events, 0)
+	}
+}
+
+// WithValidationTag overrides the struct tag holding validation rules
+// (default "validate").
+func WithValidationTag(tag string) Option {
+	return func(c *Config) {
+		c.validationTag = tag
+		c.validate.SetTagName(tag)
+	}
+}
+
+// WithKeyNamespaces reports struct validation errors under config keys
+// ("database.max_conns") instead of Go field paths
+// ("AppConfig.Database.MaxConns"). Field names are resolved from the
+// config tag, then the json tag, then the lower-cased field name.
+func WithKeyNamespaces() Option {
+	return func(c *Config) {
+		c.keyNamespaces = true
+		c.configureValidator()
+	}
+}
+
+// setValidator swaps the validator while keeping configured naming options.
+func (c *Config) setValidator(v *validator.Validate) {
+	c.validate = v
+	c.validate.SetTagName(c.validationTag)
+	c.configureValidator()
+}
+
+// configureValidator registers the config-key name function when enabled.
+// The validator is mutated, so a shared instance sees the same naming.
+func (c *Config) configureValidator() {
+	if c.keyNamespaces {
+		c.validate.RegisterTagNameFunc(configKeyName)
 	}
 }
 
@@ -647,7 +1415,7 @@ func (e ValidationErrors) Error() string {
 	return "configuration validation failed: " + strings.Join(parts, "; ")
 }
 
-func wrapValidationError(err error) error {
+func (c *Config) wrapValidationError(err error, prefix string) error {
 	ve, ok := err.(validator.ValidationErrors)
 	if !ok {
 		return err
@@ -655,13 +1423,26 @@ func wrapValidationError(err error) error {
 
 	out := make(map[string]string, len(ve))
 	for _, fe := range ve {
-		key := strings.ToLower(fe.Namespace())
-		out[key] = validationMessage(fe)
+		out[c.errorKey(fe, prefix)] = validationMessage(fe)
 	}
 
 	return ValidationErrors{Errors: out}
 }
 
+// errorKey returns the key a field error is reported under. prefix is the
+// config key the validated struct was bound from.
+func (c *Config) errorKey(fe validator.FieldError, prefix string) string {
+	if !c.keyNamespaces {
+		return strings.ToLower(fe.Namespace())
+	}
+	// Drop the root struct name; the rest is already in config-key form.
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		ns = ns[i+1:]
+	}
+	return joinKeys(prefix, ns)
+}
+
 func validationMessage(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
@@ -713,6 +1494,69 @@ func deepEqual(a, b any) bool {
 	return fmt.Sprint(a) == fmt.Sprint(b)
 }
 
+// subtree returns the keys nested under prefix with the prefix stripped.
+func subtree(data map[string]any, prefix string) map[string]any {
+	if prefix == "" {
+		return cloneMap(data)
+	}
+	out := make(map[string]any)
+	for k, v := range data {
+		if rest, ok := cutKeyPrefix(k, prefix); ok {
+			out[rest] = v
+		}
+	}
+	return out
+}
+
+// bindSubtree is subtree for binding: values nested in maps stored at
+// prefix or above it, as memory sources keep them, are included too.
+// Dotted keys win over nested values.
+func bindSubtree(data map[string]any, prefix string) map[string]any {
+	out := subtree(data, prefix)
+	for k, v := range data {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		nested := map[string]any(nil)
+		if k == prefix {
+			nested = m
+		} else if rest, under := cutKeyPrefix(prefix, k); under {
+			nested = bindSubtree(m, rest)
+		}
+		for nk, nv := range nested {
+			if _, set := out[nk]; !set {
+				out[nk] = nv
+			}
+		}
+	}
+	return out
+}
+
+// cutKeyPrefix strips a dotted key prefix, matching whole segments only.
+func cutKeyPrefix(key, prefix string) (string, bool) {
+	return strings.CutPrefix(key, prefix+".")
+}
+
+// canonicalBytes renders data as sorted key=value lines for hashing.
+func canonicalBytes(data map[string]any) []byte {
+	keys := mapKeys(data)
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s=%v\n", k, data[k])
+	}
+	return buf.Bytes()
+}
+
+func mapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func splitPath(key string) []string {
 	return strings.Split(key, ".")
 }
@@ -727,6 +1571,13 @@ func indirect(v reflect.Value) reflect.Value {
 	return v
 }
 
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
 func findField(v reflect.Value, name string) (reflect.Value, bool) {
 	t := v.Type()
 	for i := 0; i < t.NumField(); i++ {
@@ -741,6 +1592,22 @@ func findField(v reflect.Value, name string) (reflect.Value, bool) {
 	return reflect.Value{}, false
 }
 
+// configKeyName returns the config key segment for a struct field, or "-"
+// for fields tagged config:"-", which are skipped. A json:"-" tag only
+// hides the field from JSON, so like binding it falls back to the field
+// name.
+func configKeyName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("config"); tag != "" {
+		return tag
+	}
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return strings.ToLower(sf.Name)
+}
+
 // matchField checks if a struct field matches a key name.
 func matchField(sf reflect.StructField, key string) bool {
 	// 1. Check config tag
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// =============================================================================
+// Tabular Files
+// =============================================================================
+
+// CSVDecoder decodes CSV and TSV lookup tables. The first row is a header;
+// each following row becomes an entry keyed by its first column, with the
+// remaining columns as fields named by the header:
+//
+//	code,name,vat
+//	de,Germany,0.19    ->  de.name=Germany, de.vat=0.19
+//
+// Values are kept as strings; the Get* accessors convert them. Lines
+// starting with '#' are comments. Use FileSource.WithPrefix to load a
+// table under a key such as "countries".
+type CSVDecoder struct {
+	Comma      rune // field separator, defaults to ','
+	extensions []string
+}
+
+func (d CSVDecoder) Extensions() []string { return d.extensions }
+
+func (d CSVDecoder) Decode(b []byte, v any) error {
+	out, ok := v.(*map[string]any)
+	if !ok {
+		return fmt.Errorf("csv: cannot decode into %T", v)
+	}
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))))
+	if d.Comma != 0 {
+		r.Comma = d.Comma
+	}
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		*out = map[string]any{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+		if i > 0 && header[i] == "" {
+			return fmt.Errorf("csv header: column %d has no name", i+1)
+		}
+	}
+
+	table := make(map[string]any)
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		key := strings.TrimSpace(row[0])
+		if key == "" {
+			return fmt.Errorf("csv line %d: empty key", line)
+		}
+		if _, dup := table[key]; dup {
+			return fmt.Errorf("csv line %d: duplicate key %q", line, key)
+		}
+		entry := make(map[string]any, len(row)-1)
+		for i := 1; i < len(row); i++ {
+			entry[header[i]] = row[i]
+		}
+		table[key] = entry
+	}
+	*out = table
+	return nil
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// =============================================================================
+// Config File Discovery
+// =============================================================================
+
+// DefaultConfigNames are the file names looked for in each config
+// directory.
+var DefaultConfigNames = []string{"config.yaml", "config.yml", "config.json"}
+
+// UserConfigDirs returns the per-user config directories in order of
+// preference: $XDG_CONFIG_HOME (default ~/.config) on Unix, %APPDATA% on
+// Windows, and ~/Library/Application Support then ~/.config on macOS.
+func UserConfigDirs() []string {
+	var dirs []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if runtime.GOOS == "darwin" && os.Getenv("XDG_CONFIG_HOME") == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dirs = append(dirs, filepath.Join(home, ".config"))
+		}
+	}
+	return dirs
+}
+
+// SystemConfigDirs returns the system-wide config directories in order of
+// preference: $XDG_CONFIG_DIRS (default /etc/xdg) then /etc on Unix,
+// /Library/Application Support then /etc on macOS, and %ProgramData% on
+// Windows.
+func SystemConfigDirs() []string {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return []string{dir}
+		}
+		return nil
+	case "darwin":
+		return []string{"/Library/Application Support", "/etc"}
+	}
+	xdg := os.Getenv("XDG_CONFIG_DIRS")
+	if xdg == "" {
+		xdg = "/etc/xdg"
+	}
+	var dirs []string
+	for _, dir := range filepath.SplitList(xdg) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, "/etc")
+}
+
+// ConfigPaths returns the candidate files for app in dirs, in order of
+// preference: each name (DefaultConfigNames if none) under dir/app.
+func ConfigPaths(app string, dirs []string, names ...string) []string {
+	if len(names) == 0 {
+		names = DefaultConfigNames
+	}
+	paths := make([]string, 0, len(dirs)*len(names))
+	for _, dir := range dirs {
+		for _, name := range names {
+			paths = append(paths, filepath.Join(dir, app, name))
+		}
+	}
+	return paths
+}
+
+// SearchSource loads the first existing file among its candidates, or all
+// of them with more preferred files overriding less preferred ones.
+// Candidates are checked on every load and watched, so a config file
+// created later is picked up.
+type SearchSource struct {
+	BaseSource
+	candidates []string
+	all        bool
+	required   bool
+}
+
+// Search returns a source loading the first of candidates that exists.
+func Search(name string, candidates ...string) *SearchSource {
+	return SearchWithPriority(name, DefaultFilePriority, candidates...)
+}
+
+func SearchWithPriority(name string, priority int, candidates ...string) *SearchSource {
+	return &SearchSource{
+		BaseSource: NewBaseSource(name, priority),
+		candidates: candidates,
+	}
+}
+
+// All loads every existing candidate instead of only the first.
+func (s *SearchSource) All() *SearchSource {
+	s.all = true
+	return s
+}
+
+// Required makes Load fail when no candidate exists.
+func (s *SearchSource) Required() *SearchSource {
+	s.required = true
+	return s
+}
+
+// Found returns the existing candidates in order of preference.
+func (s *SearchSource) Found() []string {
+	var found []string
+	for _, path := range s.candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+func (s *SearchSource) Load() (map[string]any, error) {
+	found := s.Found()
+	if len(found) == 0 {
+		if s.required {
+			return nil, fmt.Errorf("no config file found, tried %s", strings.Join(s.candidates, ", "))
+		}
+		return map[string]any{}, nil
+	}
+	if !s.all {
+		found = found[:1]
+	}
+
+	out := make(map[string]any)
+	for i := len(found) - 1; i >= 0; i-- {
+		data, err := File(found[i]).Load()
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", found[i], err)
+		}
+		for k, v := range data {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// WatchTargets reports every candidate, existing or not.
+func (s *SearchSource) WatchTargets() []SourceTarget {
+	targets := make([]SourceTarget, len(s.candidates))
+	for i, path := range s.candidates {
+		targets[i] = SourceTarget{Kind: WatchTargetFile, Path: path}
+	}
+	return targets
+}
+
+// =============================================================================
+// Builder Integration
+// =============================================================================
+
+// DiscoveryOption configures AddUserConfig and AddSystemConfig.
+type DiscoveryOption func(*SearchSource)
+
+// DiscoverAll loads every config file found rather than the first; more
+// preferred directories override less preferred ones.
+func DiscoverAll() DiscoveryOption {
+	return func(s *SearchSource) { s.all = true }
+}
+
+// DiscoverRequired fails loading when no config file is found.
+func DiscoverRequired() DiscoveryOption {
+	return func(s *SearchSource) { s.required = true }
+}
+
+// AddUserConfig adds app's per-user config file, found in UserConfigDirs
+// as app/config.yaml (or .yml, .json). A missing file contributes nothing
+// unless DiscoverRequired is set.
+func (b *Builder) AddUserConfig(app string, opts ...DiscoveryOption) *Builder {
+	return b.addDiscovered("user-config:"+app, ConfigPaths(app, UserConfigDirs()), opts)
+}
+
+// AddSystemConfig adds app's system-wide config file, found in
+// SystemConfigDirs as app/config.yaml (or .yml, .json). Add it before
+// AddUserConfig so user settings override system ones.
+func (b *Builder) AddSystemConfig(app string, opts ...DiscoveryOption) *Builder {
+	return b.addDiscovered("system-config:"+app, ConfigPaths(app, SystemConfigDirs()), opts)
+}
+
+func (b *Builder) addDiscovered(name string, candidates []string, opts []DiscoveryOption) *Builder {
+	src := SearchWithPriority(name, b.factory.defaultPriority, candidates...)
+	for _, opt := range opts {
+		opt(src)
+	}
+	return b.AddSource(src)
+}
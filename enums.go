@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/os-golib/go-config/rules"
+)
+
+// =============================================================================
+// Enums
+// =============================================================================
+
+// Enum is implemented by enum-like types that list their valid names:
+//
+//	type LogLevel string
+//
+//	func (LogLevel) Values() []string { return []string{"debug", "info", "warn", "error"} }
+//
+// Values is called on the zero value. For integer types, as with iota
+// constants, Values()[i] names the value i.
+type Enum interface {
+	Values() []string
+}
+
+// WithEnum registers T for binding and validation from its Values: struct
+// fields of type T accept only those names, and keys get a oneof rule,
+// combined with any rule already registered for them. It panics if T is
+// neither a string nor an integer type or lists no values.
+func WithEnum[T Enum](keys ...string) Option {
+	var zero T
+	names := zero.Values()
+	typ := reflect.TypeFor[T]()
+	values := make([]reflect.Value, len(names))
+	for i, name := range names {
+		v := reflect.New(typ).Elem()
+		switch {
+		case typ.Kind() == reflect.String:
+			v.SetString(name)
+		case v.CanInt():
+			v.SetInt(int64(i))
+		case v.CanUint():
+			v.SetUint(uint64(i))
+		default:
+			panic(fmt.Sprintf("config: enum %s must be a string or integer type", typ))
+		}
+		values[i] = v
+	}
+	return withEnum(typ, names, values, keys)
+}
+
+// WithEnumValues registers values, named by their String method, as the
+// valid values of T, e.g. the constants of a stringer-generated type. It
+// otherwise works like WithEnum.
+func WithEnumValues[T fmt.Stringer](values []T, keys ...string) Option {
+	names := make([]string, len(values))
+	rvs := make([]reflect.Value, len(values))
+	for i, v := range values {
+		names[i] = v.String()
+		rvs[i] = reflect.ValueOf(v)
+	}
+	return withEnum(reflect.TypeFor[T](), names, rvs, keys)
+}
+
+// EnumRule returns a oneof rule for key accepting T's Values.
+func EnumRule[T Enum](key string) *Rule {
+	var zero T
+	return Rules.OneOf(key, zero.Values()...)
+}
+
+func withEnum(typ reflect.Type, names []string, values []reflect.Value, keys []string) Option {
+	if len(names) == 0 {
+		panic(fmt.Sprintf("config: enum %s has no values", typ))
+	}
+	byName := make(map[string]reflect.Value, len(names))
+	for i, name := range names {
+		byName[name] = values[i]
+	}
+	valid := strings.Join(names, " ")
+
+	return func(c *Config) {
+		c.converter.RegisterTypeConverter(typ, func(dst reflect.Value, raw any) error {
+			v, ok := byName[fmt.Sprint(raw)]
+			if !ok {
+				return fmt.Errorf("invalid %s %q: must be one of: %s", typ, fmt.Sprint(raw), valid)
+			}
+			dst.Set(v)
+			return nil
+		})
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, key := range keys {
+			rule := rules.New(key)
+			if existing, ok := c.validationRules[key]; ok {
+				rule = existing.WithPrefix("")
+			}
+			c.validationRules[key] = rule.Add(TagOneOf, valid)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// Child Process Environment
+// =============================================================================
+
+// Environ renders config keys as KEY=VALUE entries for a child process,
+// the reverse of an env source: "db.max_conns" becomes prefix+"DB_MAX_CONNS".
+// Keys are selected by patterns, using the MarkSecret syntax, or all keys
+// when none are given, and by every filter:
+//
+//	cmd.Env = append(os.Environ(), cfg.Environ("APP_", []string{"db.*"}, config.ExcludeSecrets)...)
+//
+// Secret values are passed unredacted unless excluded. Lists are joined
+// with commas, maps are JSON and nulls are empty. List elements are
+// skipped in favour of their list, and when two keys map to the same
+// name the first in key order wins. Entries are sorted by name.
+func (c *Config) Environ(prefix string, patterns []string, filters ...ExportFilter) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.data))
+	for k := range c.data {
+		if hasIndexSegment(k) || (len(patterns) > 0 && !matchesAny(patterns, k)) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]bool, len(keys))
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := c.valueOf(k, c.data[k])
+		if !acceptAll(filters, v) {
+			continue
+		}
+		name := prefix + envName(k)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		env = append(env, name+"="+envValue(v.Raw()))
+	}
+	sort.Strings(env)
+	return env
+}
+
+// envName turns "db.max-conns" into "DB_MAX_CONNS".
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, key)
+}
+
+func envValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case []any, []string:
+		s, _ := asStringSlice(x)
+		return strings.Join(s, ",")
+	case map[string]any:
+		raw, err := json.Marshal(x)
+		if err != nil {
+			break
+		}
+		return string(raw)
+	}
+	s, _ := asString(v)
+	return s
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Jsonnet and CUE Sources
+// =============================================================================
+
+// Evaluator evaluates a configuration program to JSON. file is a
+// slash-separated path within fsys, and imports must be resolved through
+// fsys, which is confined to the source's root directory. Adapt your
+// language runtime to this interface, for example github.com/google/go-jsonnet
+// with an importer reading from fsys, or cuelang.org/go with fsys as the
+// load overlay; it should abort when ctx is done. Until an evaluation that
+// ignored the timeout returns, the source fails loads with ErrStillRunning.
+type Evaluator interface {
+	Evaluate(ctx context.Context, fsys fs.FS, file string) ([]byte, error)
+}
+
+// EvaluatorFunc adapts a function to Evaluator.
+type EvaluatorFunc func(ctx context.Context, fsys fs.FS, file string) ([]byte, error)
+
+func (f EvaluatorFunc) Evaluate(ctx context.Context, fsys fs.FS, file string) ([]byte, error) {
+	return f(ctx, fsys, file)
+}
+
+// EvalOptions configure an evaluated source.
+type EvalOptions struct {
+	Root    string        // import root, defaults to the file's directory
+	Timeout time.Duration // per-evaluation limit, defaults to 10s
+}
+
+// EvalSource loads configuration by evaluating a Jsonnet or CUE file. The
+// result must be a JSON object; nested objects are flattened to dot keys.
+// Imports are restricted to Root, symlinks included. Every file read during
+// the last evaluation is watched, so editing an imported library reloads.
+type EvalSource struct {
+	BaseSource
+	lang string
+	path string
+	eval Evaluator
+	opts EvalOptions
+
+	calls boundedCalls
+	mu    sync.Mutex
+	files []string // files read by the last evaluation
+}
+
+// Jsonnet returns a source evaluating a Jsonnet file.
+func Jsonnet(path string, eval Evaluator, opts EvalOptions) *EvalSource {
+	return JsonnetWithPriority(path, eval, opts, DefaultFilePriority)
+}
+
+func JsonnetWithPriority(path string, eval Evaluator, opts EvalOptions, priority int) *EvalSource {
+	return newEvalSource("jsonnet", path, eval, opts, priority)
+}
+
+// CUE returns a source evaluating a CUE file.
+func CUE(path string, eval Evaluator, opts EvalOptions) *EvalSource {
+	return CUEWithPriority(path, eval, opts, DefaultFilePriority)
+}
+
+func CUEWithPriority(path string, eval Evaluator, opts EvalOptions, priority int) *EvalSource {
+	return newEvalSource("cue", path, eval, opts, priority)
+}
+
+func newEvalSource(lang, path string, eval Evaluator, opts EvalOptions, priority int) *EvalSource {
+	if opts.Root == "" {
+		opts.Root = filepath.Dir(path)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &EvalSource{
+		BaseSource: NewBaseSource(lang+":"+path, priority, path),
+		lang:       lang,
+		path:       path,
+		eval:       eval,
+		opts:       opts,
+	}
+}
+
+func (s *EvalSource) Load() (map[string]any, error) {
+	file, err := filepath.Rel(s.opts.Root, s.path)
+	if err != nil || file == ".." || strings.HasPrefix(file, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s %s: file is outside root %s", s.lang, s.path, s.opts.Root)
+	}
+
+	root, err := os.OpenRoot(s.opts.Root)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", s.lang, s.path, err)
+	}
+	defer root.Close()
+	fsys := &recordingFS{fsys: root.FS()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+
+	raw, err := runBounded(&s.calls, ctx, func(ctx context.Context) ([]byte, error) {
+		return s.eval.Evaluate(ctx, fsys, filepath.ToSlash(file))
+	})
+	s.mu.Lock()
+	s.files = fsys.files(s.opts.Root)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", s.lang, s.path, err)
+	}
+
+	var decoded map[string]any
+	if err := decodeJSON(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("%s %s: decode output: %w", s.lang, s.path, err)
+	}
+	return flattenToDot(decoded), nil
+}
+
+// WatchTargets reports the evaluated file and every file it imported.
+func (s *EvalSource) WatchTargets() []SourceTarget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets := []SourceTarget{{Kind: WatchTargetFile, Path: s.path}}
+	for _, f := range s.files {
+		if f != filepath.Clean(s.path) {
+			targets = append(targets, SourceTarget{Kind: WatchTargetFile, Path: f})
+		}
+	}
+	return targets
+}
+
+// recordingFS notes the files an evaluator opens.
+type recordingFS struct {
+	fsys fs.FS
+
+	mu     sync.Mutex
+	opened map[string]bool
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	f, err := r.fsys.Open(name)
+	if err == nil {
+		r.mu.Lock()
+		if r.opened == nil {
+			r.opened = make(map[string]bool)
+		}
+		r.opened[name] = true
+		r.mu.Unlock()
+	}
+	return f, err
+}
+
+// files returns the opened regular files as paths under root.
+func (r *recordingFS) files(root string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []string
+	for name := range r.opened {
+		if info, err := fs.Stat(r.fsys, name); err == nil && !info.IsDir() {
+			out = append(out, filepath.Join(root, filepath.FromSlash(name)))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
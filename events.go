@@ -0,0 +1,126 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Lifecycle Events
+// =============================================================================
+
+// EventType identifies a configuration lifecycle event.
+type EventType string
+
+const (
+	EventLoadStarted      EventType = "load_started"
+	EventSourceLoaded     EventType = "source_loaded"
+	EventLoadFailed       EventType = "load_failed"
+	EventValidated        EventType = "validated"
+	EventChangesApplied   EventType = "changes_applied"
+	EventWatcherStopped   EventType = "watcher_stopped"
+	EventReloadStage      EventType = "reload_stage"
+	defaultEventBuffer              = 64
+	defaultRetainedEvents           = 128
+)
+
+// Event is a typed lifecycle event. Fields not relevant to a type are zero.
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	Source   string        // source name (SourceLoaded, LoadFailed), or plan name (ReloadStage)
+	Keys     int           // keys loaded or changed
+	Duration time.Duration // source load time, total load time for Validated, or stage time
+	Err      error         // failure cause (LoadFailed, Validated, WatcherStopped, ReloadStage)
+	Changes  *ChangeSet    // applied changes (ChangesApplied)
+	Stage    ReloadStage   // plan stage (ReloadStage)
+}
+
+// Events subscribes to lifecycle events with a default buffer. The channel
+// is closed by Close.
+func (c *Config) Events() <-chan Event {
+	ch, _ := c.Subscribe(defaultEventBuffer)
+	return ch
+}
+
+// Subscribe returns a buffered event channel and a function that cancels the
+// subscription. Events are dropped for subscribers whose buffer is full, so
+// a slow consumer never blocks loading.
+func (c *Config) Subscribe(buffer int) (<-chan Event, func()) {
+	return c.events.subscribe(buffer)
+}
+
+// eventBus fans events out to subscribers without blocking and keeps the
+// most recent events for Archive.
+type eventBus struct {
+	mu     sync.Mutex
+	subs   map[int]chan Event
+	nextID int
+	closed bool
+	recent []Event
+	retain int
+}
+
+func (b *eventBus) subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, buffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs == nil {
+		b.subs = make(map[int]chan Event)
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if sub, ok := b.subs[id]; ok {
+			delete(b.subs, id)
+			close(sub)
+		}
+	}
+}
+
+func (b *eventBus) emit(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.retain > 0 {
+		b.recent = append(b.recent, ev)
+		if over := len(b.recent) - b.retain; over > 0 {
+			b.recent = append(b.recent[:0], b.recent[over:]...)
+		}
+	}
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// retained returns a copy of the recent events, oldest first.
+func (b *eventBus) retained() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Event(nil), b.recent...)
+}
+
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for id, ch := range b.subs {
+		delete(b.subs, id)
+		close(ch)
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Exec Source
+// =============================================================================
+
+// ExecOptions configure an exec source.
+type ExecOptions struct {
+	Args []string // arguments passed to the helper
+	Env  []string // extra KEY=VALUE pairs appended to the process environment
+	Dir  string   // working directory, defaults to the current one
+
+	// Watch keeps the helper running. It must print one JSON object per
+	// line, each a complete snapshot; the latest snapshot wins.
+	Watch bool
+
+	Timeout         time.Duration // per-run limit in one-shot mode, defaults to 30s
+	RefreshInterval time.Duration // minimum time between runs in one-shot mode, defaults to 1m
+}
+
+// ExecSource loads configuration from a helper binary, so proprietary
+// backends can be added without forking the library. The helper prints a
+// JSON object to stdout and exits zero; nested objects are flattened to dot
+// keys. Anything written to stderr is included in errors. CONFIG_EXEC_MODE
+// is set to "once" or "watch" in the helper's environment.
+//
+// In watch mode the helper stays running and prints a new line whenever its
+// configuration changes. A helper that exits is restarted on the next Poll.
+type ExecSource struct {
+	BaseSource
+	command string
+	opts    ExecOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	fetched  bool
+	nextPoll time.Time
+
+	proc *execProcess
+}
+
+func Exec(command string, opts ExecOptions) *ExecSource {
+	return ExecWithPriority(command, opts, DefaultRemotePriority)
+}
+
+func ExecWithPriority(command string, opts ExecOptions, priority int) *ExecSource {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	return &ExecSource{
+		BaseSource: NewBaseSource("exec:"+command, priority),
+		command:    command,
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Load returns the helper's output, running or starting it on first use.
+func (s *ExecSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.refresh(); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reruns a one-shot helper once the refresh interval has elapsed, or
+// picks up the newest snapshot from a watching helper.
+func (s *ExecSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.opts.Watch && s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.refresh()
+}
+
+// Close stops a watching helper.
+func (s *ExecSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.proc == nil {
+		return nil
+	}
+	s.proc.stop()
+	s.proc = nil
+	return nil
+}
+
+func (s *ExecSource) refresh() (bool, error) {
+	var data map[string]any
+	var err error
+	if s.opts.Watch {
+		data, err = s.watch()
+	} else {
+		data, err = s.run()
+	}
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	changed := !s.fetched || !bytes.Equal(canonicalBytes(data), canonicalBytes(s.data))
+	s.data = data
+	s.fetched = true
+	return changed, nil
+}
+
+func (s *ExecSource) run() (map[string]any, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := s.cmd(ctx, "once")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, s.errorf(err, stderr.String())
+	}
+	return s.decode(stdout.Bytes())
+}
+
+// watch returns the newest snapshot from the running helper, starting it
+// and waiting for its first snapshot when needed. It returns nil when
+// nothing new arrived.
+func (s *ExecSource) watch() (map[string]any, error) {
+	if s.proc == nil {
+		proc, err := s.start()
+		if err != nil {
+			return nil, err
+		}
+		s.proc = proc
+		if !proc.waitFirst(s.opts.Timeout) {
+			err := proc.exitErr()
+			proc.stop()
+			s.proc = nil
+			if err == nil {
+				err = fmt.Errorf("exec %s: no output within %s", s.command, s.opts.Timeout)
+			}
+			return nil, err
+		}
+	}
+
+	data, err := s.proc.latest()
+	if err != nil || data != nil {
+		return data, err
+	}
+	if err := s.proc.exitErr(); err != nil {
+		s.proc = nil
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *ExecSource) start() (*execProcess, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var stderr bytes.Buffer
+	cmd := s.cmd(ctx, "watch")
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("exec %s: %w", s.command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("exec %s: %w", s.command, err)
+	}
+
+	p := &execProcess{cancel: cancel, first: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			data, err := s.decode(line)
+			p.publish(data, err)
+		}
+		scanErr := scanner.Err()
+		if scanErr != nil {
+			cancel() // unblock a helper stuck writing an oversized line
+		}
+		err := cmd.Wait()
+		switch {
+		case scanErr != nil:
+			p.finish(fmt.Errorf("exec %s: read output: %w", s.command, scanErr))
+		case ctx.Err() == nil:
+			if err == nil {
+				err = errors.New("exited")
+			}
+			p.finish(s.errorf(err, stderr.String()))
+		default:
+			p.finish(nil)
+		}
+	}()
+	return p, nil
+}
+
+func (s *ExecSource) cmd(ctx context.Context, mode string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, s.command, s.opts.Args...)
+	cmd.Dir = s.opts.Dir
+	cmd.Env = append(os.Environ(), s.opts.Env...)
+	cmd.Env = append(cmd.Env, "CONFIG_EXEC_MODE="+mode)
+	return cmd
+}
+
+func (s *ExecSource) decode(raw []byte) (map[string]any, error) {
+	var decoded map[string]any
+	if err := decodeJSON(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("exec %s: decode output: %w", s.command, err)
+	}
+	return flattenToDot(decoded), nil
+}
+
+func (s *ExecSource) errorf(err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("exec %s: %w: %s", s.command, err, msg)
+	}
+	return fmt.Errorf("exec %s: %w", s.command, err)
+}
+
+// execProcess tracks a watching helper and the snapshots it printed.
+type execProcess struct {
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	pending map[string]any
+	err     error // decode error of the newest line
+	exit    error
+	first   chan struct{}
+	once    sync.Once
+	done    chan struct{}
+}
+
+func (p *execProcess) publish(data map[string]any, err error) {
+	p.mu.Lock()
+	p.pending, p.err = data, err
+	p.mu.Unlock()
+	p.once.Do(func() { close(p.first) })
+}
+
+func (p *execProcess) finish(err error) {
+	p.mu.Lock()
+	p.exit = err
+	p.mu.Unlock()
+	p.once.Do(func() { close(p.first) })
+	close(p.done)
+}
+
+// waitFirst waits for the first snapshot or for the helper to exit.
+func (p *execProcess) waitFirst(timeout time.Duration) bool {
+	select {
+	case <-p.first:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.pending != nil || p.err != nil
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// latest returns and clears the newest snapshot.
+func (p *execProcess) latest() (map[string]any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.pending, p.err
+	p.pending, p.err = nil, nil
+	return data, err
+}
+
+func (p *execProcess) exitErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exit
+}
+
+func (p *execProcess) stop() {
+	p.cancel()
+	<-p.done
+}
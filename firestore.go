@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Firestore Document Source
+// =============================================================================
+
+// FirestoreOptions configure a source reading Firestore documents, in the
+// style of the retired Runtime Config service: one document per config set.
+type FirestoreOptions struct {
+	Project   string
+	Database  string   // defaults to "(default)"
+	Documents []string // document paths such as "config/app"; later documents win
+	Prefix    string   // key namespace for the fields
+
+	// Subscription is an optional Pub/Sub pull subscription receiving a
+	// message whenever the documents change (e.g. from a Cloud Function
+	// trigger). When set, Poll only refetches after a notification arrives.
+	Subscription    string
+	RefreshInterval time.Duration // minimum time between polls, defaults to 30s
+
+	Credentials GCPTokenProvider // defaults to ApplicationDefaultCredentials
+	Auth        AuthProvider     // replaces the default Credentials when set
+	Client      *http.Client
+}
+
+// FirestoreSource loads document fields as configuration keys. Map fields
+// become nested keys and arrays become indexed keys, like file sources.
+type FirestoreSource struct {
+	BaseSource
+	opts     FirestoreOptions
+	notifier *pubSubNotifier
+
+	mu       sync.Mutex
+	data     map[string]any
+	updated  map[string]string // document -> updateTime
+	fetched  bool
+	nextPoll time.Time
+}
+
+func Firestore(opts FirestoreOptions) *FirestoreSource {
+	return FirestoreWithPriority(opts, DefaultRemotePriority)
+}
+
+func FirestoreWithPriority(opts FirestoreOptions, priority int) *FirestoreSource {
+	if opts.Database == "" {
+		opts.Database = "(default)"
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 30 * time.Second
+	}
+	if opts.Credentials == nil && opts.Auth == nil {
+		opts.Credentials = ApplicationDefaultCredentials()
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+
+	s := &FirestoreSource{
+		BaseSource: NewBaseSource("firestore:"+opts.Project, priority),
+		opts:       opts,
+		data:       map[string]any{},
+	}
+	if opts.Subscription != "" {
+		s.notifier = &pubSubNotifier{subscription: opts.Subscription, client: opts.Client, creds: opts.Credentials}
+	}
+	return s
+}
+
+// Remote reports that the source needs the network.
+func (s *FirestoreSource) Remote() bool { return true }
+
+// Load returns the document fields, fetching them on first use.
+func (s *FirestoreSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether any document was updated since the last fetch.
+func (s *FirestoreSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if s.fetched && s.notifier != nil {
+		notified, err := s.notifier.pull(ctx)
+		if err != nil || !notified {
+			return false, err
+		}
+	} else if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch(ctx)
+}
+
+type firestoreDocument struct {
+	Fields     map[string]firestoreValue `json:"fields"`
+	UpdateTime string                    `json:"updateTime"`
+}
+
+type firestoreValue struct {
+	StringValue    *string  `json:"stringValue"`
+	IntegerValue   *string  `json:"integerValue"`
+	DoubleValue    *float64 `json:"doubleValue"`
+	BooleanValue   *bool    `json:"booleanValue"`
+	TimestampValue *string  `json:"timestampValue"`
+	ReferenceValue *string  `json:"referenceValue"`
+	MapValue       *struct {
+		Fields map[string]firestoreValue `json:"fields"`
+	} `json:"mapValue"`
+	ArrayValue *struct {
+		Values []firestoreValue `json:"values"`
+	} `json:"arrayValue"`
+}
+
+// plain converts a typed Firestore value to the value a decoded file
+// would hold.
+func (v firestoreValue) plain() any {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.IntegerValue != nil:
+		if n, err := strconv.ParseInt(*v.IntegerValue, 10, 64); err == nil {
+			return int(n)
+		}
+		return *v.IntegerValue
+	case v.DoubleValue != nil:
+		return *v.DoubleValue
+	case v.BooleanValue != nil:
+		return *v.BooleanValue
+	case v.TimestampValue != nil:
+		return *v.TimestampValue
+	case v.ReferenceValue != nil:
+		return *v.ReferenceValue
+	case v.MapValue != nil:
+		m := make(map[string]any, len(v.MapValue.Fields))
+		for k, field := range v.MapValue.Fields {
+			m[k] = field.plain()
+		}
+		return m
+	case v.ArrayValue != nil:
+		list := make([]any, len(v.ArrayValue.Values))
+		for i, item := range v.ArrayValue.Values {
+			list[i] = item.plain()
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+func (s *FirestoreSource) fetch(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	data := make(map[string]any)
+	updated := make(map[string]string, len(s.opts.Documents))
+	for _, doc := range s.opts.Documents {
+		endpoint := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/%s/documents/%s",
+			s.opts.Project, s.opts.Database, doc)
+		resp, body, err := gcpRequest(ctx, s.opts.Client, s.opts.Credentials, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return false, fmt.Errorf("firestore: %w", err)
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("firestore: get %s: %s: %s", doc, resp.Status, body)
+		}
+
+		var document firestoreDocument
+		if err := json.Unmarshal(body, &document); err != nil {
+			return false, fmt.Errorf("firestore: decode %s: %w", doc, err)
+		}
+		for name, field := range document.Fields {
+			flatten(joinKeys(s.opts.Prefix, name), field.plain(), data)
+		}
+		updated[doc] = document.UpdateTime
+	}
+
+	changed := !s.fetched || !maps.Equal(updated, s.updated)
+	s.data, s.updated = data, updated
+	s.fetched = true
+	return changed, nil
+}
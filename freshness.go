@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Struct Projections
+// =============================================================================
+
+// FieldFreshness reports where a bound struct field's value came from and
+// when it last changed.
+type FieldFreshness struct {
+	Field     string    // Go field path, e.g. "Server.Timeout"
+	Key       string    // config key, e.g. "server.timeout"
+	Value     any       // the bound value
+	Source    string    // source that provided the value; empty if unset
+	ChangedAt time.Time // when the value was first loaded or last changed; zero if unset
+	Changed   bool      // whether the most recent load changed it
+}
+
+// Projection is a read-only view of the config bound to a T, with the
+// freshness of each field. It is a snapshot and does not follow reloads.
+type Projection[T any] struct {
+	value  T
+	fields []FieldFreshness
+	at     time.Time
+}
+
+// Project binds the keys under prefix to a fresh T, like BindPrefix, and
+// records per field its source, when it last changed and whether the
+// latest reload changed it. Fields backed by maps, lists or nested maps
+// report the most recently changed key beneath them.
+func Project[T any](c *Config, prefix string) (*Projection[T], error) {
+	c.mu.RLock()
+	data := bindSubtree(c.data, prefix)
+	c.auditBind(data, prefix)
+	p := &Projection[T]{at: time.Now()}
+	fields := projectFields(reflect.TypeFor[T](), "", prefix)
+	for i := range fields {
+		c.fieldFreshness(&fields[i])
+	}
+	c.mu.RUnlock()
+
+	if err := c.bindMapToStruct(data, &p.value); err != nil {
+		if prefix != "" {
+			return nil, fmt.Errorf("%s: %w", prefix, err)
+		}
+		return nil, err
+	}
+	rv := reflect.ValueOf(p.value)
+	for i := range fields {
+		fields[i].Value = fieldByPath(rv, fields[i].Field)
+	}
+	p.fields = fields
+	return p, nil
+}
+
+// Value returns a copy of the bound struct. Maps and slices in it are
+// shared with the projection and must not be modified.
+func (p *Projection[T]) Value() T { return p.value }
+
+// At returns when the projection was taken.
+func (p *Projection[T]) At() time.Time { return p.at }
+
+// Fields returns the freshness of every field in declaration order.
+func (p *Projection[T]) Fields() []FieldFreshness {
+	return append([]FieldFreshness(nil), p.fields...)
+}
+
+// Field returns the freshness of the field at a Go field path such as
+// "Server.Timeout".
+func (p *Projection[T]) Field(path string) (FieldFreshness, bool) {
+	for _, f := range p.fields {
+		if f.Field == path {
+			return f, true
+		}
+	}
+	return FieldFreshness{}, false
+}
+
+// ChangedFields returns the fields changed by the most recent load.
+func (p *Projection[T]) ChangedFields() []FieldFreshness {
+	var out []FieldFreshness
+	for _, f := range p.fields {
+		if f.Changed {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// projectFields lists the leaf fields of t with their config keys,
+// following the naming rules of binding.
+func projectFields(t reflect.Type, field, key string) []FieldFreshness {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return []FieldFreshness{{Field: field, Key: key}}
+	}
+	var out []FieldFreshness
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := configKeyName(sf)
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		fieldKey := joinKeys(key, name)
+		if sf.Anonymous && sf.Tag.Get("config") == "" && sf.Tag.Get("json") == "" {
+			fieldKey = key
+		}
+		out = append(out, projectFields(sf.Type, joinFieldPath(field, sf.Name), fieldKey)...)
+	}
+	return out
+}
+
+func joinFieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// fieldFreshness fills in the source and change time of f from its key,
+// the keys beneath it, or the nearest enclosing key holding a nested map.
+// The caller must hold c.mu.
+func (c *Config) fieldFreshness(f *FieldFreshness) {
+	var keys []string
+	if _, ok := c.data[f.Key]; ok {
+		keys = append(keys, f.Key)
+	}
+	for k := range c.data {
+		if _, under := cutKeyPrefix(k, f.Key); under {
+			keys = append(keys, k)
+		}
+	}
+	for parent := f.Key; len(keys) == 0; {
+		i := strings.LastIndexByte(parent, '.')
+		if i < 0 {
+			break
+		}
+		parent = parent[:i]
+		if _, ok := c.data[parent].(map[string]any); ok {
+			keys = append(keys, parent)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, changed := c.lastChanged[k]; changed {
+			f.Changed = true
+		}
+		at := c.changedAt[k]
+		if f.Source == "" || at.After(f.ChangedAt) {
+			f.Source, f.ChangedAt = c.provenance[k], at
+		}
+	}
+}
+
+// fieldByPath returns the value at a Go field path, or nil if a pointer on
+// the way is nil.
+func fieldByPath(v reflect.Value, path string) any {
+	if path == "" {
+		return v.Interface()
+	}
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByName(name)
+	}
+	return v.Interface()
+}
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// GCP Credentials
+// =============================================================================
+
+const gcpScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GCPTokenProvider returns OAuth2 access tokens for Google Cloud APIs.
+type GCPTokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// GCPTokenFunc adapts a function to GCPTokenProvider.
+type GCPTokenFunc func(ctx context.Context) (string, error)
+
+func (f GCPTokenFunc) Token(ctx context.Context) (string, error) { return f(ctx) }
+
+// ApplicationDefaultCredentials resolves credentials like Google's client
+// libraries: the JSON file named by GOOGLE_APPLICATION_CREDENTIALS, then the
+// gcloud well-known file, then the GCE/GKE/Cloud Run metadata server.
+// Service account keys and gcloud user credentials are supported.
+// Tokens are cached until shortly before they expire.
+func ApplicationDefaultCredentials() GCPTokenProvider {
+	return &gcpCredentials{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type gcpCredentialsFile struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type gcpCredentials struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (g *gcpCredentials) Token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token != "" && time.Until(g.expires) > 5*time.Minute {
+		return g.token, nil
+	}
+
+	file, err := g.credentialsFile()
+	if err != nil {
+		return "", fmt.Errorf("gcp credentials: %w", err)
+	}
+
+	var form url.Values
+	endpoint := "https://oauth2.googleapis.com/token"
+	switch {
+	case file == nil:
+		return g.metadataToken(ctx)
+	case file.Type == "service_account":
+		if file.TokenURI != "" {
+			endpoint = file.TokenURI
+		}
+		assertion, err := gcpAssertion(file, endpoint, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("gcp credentials: %w", err)
+		}
+		form = url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {assertion},
+		}
+	case file.Type == "authorized_user":
+		form = url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {file.ClientID},
+			"client_secret": {file.ClientSecret},
+			"refresh_token": {file.RefreshToken},
+		}
+	default:
+		return "", fmt.Errorf("gcp credentials: unsupported credentials type %q", file.Type)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return g.exchange(req)
+}
+
+// credentialsFile returns the ADC file, or nil when none exists.
+func (g *gcpCredentials) credentialsFile() (*gcpCredentialsFile, error) {
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, nil
+		}
+		path = filepath.Join(dir, "gcloud", "application_default_credentials.json")
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file gcpCredentialsFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+func (g *gcpCredentials) metadataToken(ctx context.Context) (string, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return g.exchange(req)
+}
+
+func (g *gcpCredentials) exchange(req *http.Request) (string, error) {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gcp credentials: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("gcp credentials: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gcp credentials: %s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("gcp credentials: decode token: %w", err)
+	}
+	g.token = out.AccessToken
+	g.expires = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	return g.token, nil
+}
+
+// gcpAssertion builds the RS256-signed JWT a service account exchanges for
+// an access token.
+func gcpAssertion(file *gcpCredentialsFile, audience string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(file.PrivateKey))
+	if block == nil {
+		return "", errors.New("invalid private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", fmt.Errorf("parse private key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("private key is not RSA")
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": file.PrivateKeyID})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   file.ClientEmail,
+		"scope": gcpScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// =============================================================================
+// GCP Requests
+// =============================================================================
+
+// gcpRequest issues an authenticated request against a Google API and
+// returns the response with its body fully read.
+func gcpRequest(ctx context.Context, client *http.Client, creds GCPTokenProvider,
+	method, endpoint string, payload any,
+) (*http.Response, []byte, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if creds != nil { // nil when the client carries an AuthProvider
+		token, err := creds.Token(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp, data, err
+}
+
+// =============================================================================
+// Pub/Sub Notifications
+// =============================================================================
+
+// pubSubNotifier drains a Pub/Sub pull subscription, reporting whether any
+// notification arrived since the last call.
+type pubSubNotifier struct {
+	subscription string // projects/<p>/subscriptions/<s>
+	client       *http.Client
+	creds        GCPTokenProvider
+}
+
+func (n *pubSubNotifier) pull(ctx context.Context) (bool, error) {
+	endpoint := "https://pubsub.googleapis.com/v1/" + n.subscription + ":pull"
+	resp, body, err := gcpRequest(ctx, n.client, n.creds, http.MethodPost, endpoint,
+		map[string]any{"maxMessages": 100, "returnImmediately": true})
+	if err != nil {
+		return false, fmt.Errorf("pubsub: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pubsub: pull: %s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		ReceivedMessages []struct {
+			AckID string `json:"ackId"`
+		} `json:"receivedMessages"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return false, fmt.Errorf("pubsub: decode pull: %w", err)
+	}
+	if len(out.ReceivedMessages) == 0 {
+		return false, nil
+	}
+
+	ackIDs := make([]string, len(out.ReceivedMessages))
+	for i, m := range out.ReceivedMessages {
+		ackIDs[i] = m.AckID
+	}
+	endpoint = "https://pubsub.googleapis.com/v1/" + n.subscription + ":acknowledge"
+	resp, body, err = gcpRequest(ctx, n.client, n.creds, http.MethodPost, endpoint,
+		map[string]any{"ackIds": ackIDs})
+	if err != nil {
+		return true, fmt.Errorf("pubsub: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return true, fmt.Errorf("pubsub: acknowledge: %s: %s", resp.Status, body)
+	}
+	return true, nil
+}
@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"time"
+
+	"github.com/os-golib/go-config/middleware"
+	"github.com/os-golib/go-config/source"
 )
 
 // =============================================================================
@@ -45,6 +49,7 @@ type HookManager struct {
 	postLoad []PostLoadHook
 	preBind  []PreBindHook
 	postBind []PostBindHook
+	all      []Hook // registration order
 }
 
 // NewHookManager creates a new hook manager.
@@ -57,8 +62,20 @@ func NewHookManager() *HookManager {
 	}
 }
 
+// clone returns a manager with the same registered hooks.
+func (hm *HookManager) clone() *HookManager {
+	return &HookManager{
+		preLoad:  append([]PreLoadHook(nil), hm.preLoad...),
+		postLoad: append([]PostLoadHook(nil), hm.postLoad...),
+		preBind:  append([]PreBindHook(nil), hm.preBind...),
+		postBind: append([]PostBindHook(nil), hm.postBind...),
+		all:      append([]Hook(nil), hm.all...),
+	}
+}
+
 // Register registers a hook (auto-detects type).
 func (hm *HookManager) Register(hook Hook) {
+	hm.all = append(hm.all, hook)
 	if h, ok := hook.(PreLoadHook); ok {
 		hm.preLoad = append(hm.preLoad, h)
 		sortHooks(hm.preLoad)
@@ -79,7 +96,16 @@ func (hm *HookManager) Register(hook Hook) {
 
 // ExecutePreLoad executes all pre-load hooks.
 func (hm *HookManager) ExecutePreLoad(c *Config) error {
+	return hm.executePreLoad(c, nil)
+}
+
+// executePreLoad runs the pre-load hooks, recording each one run in report
+// when it is non-nil.
+func (hm *HookManager) executePreLoad(c *Config, report *LoadReport) error {
 	for _, hook := range hm.preLoad {
+		if report != nil {
+			report.Hooks = append(report.Hooks, hook.Name())
+		}
 		if err := hook.OnPreLoad(c); err != nil {
 			return fmt.Errorf("pre-load hook %s: %w", hook.Name(), err)
 		}
@@ -89,10 +115,28 @@ func (hm *HookManager) ExecutePreLoad(c *Config) error {
 
 // ExecutePostLoad executes all post-load hooks.
 func (hm *HookManager) ExecutePostLoad(c *Config, data map[string]any) error {
+	return hm.executePostLoad(c, data, nil, nil)
+}
+
+// executePostLoad runs the post-load hooks, attributing keys a hook adds
+// to "hook:<name>" in provenance and recording each hook run in report when
+// they are non-nil.
+func (hm *HookManager) executePostLoad(c *Config, data map[string]any, provenance map[string]string, report *LoadReport) error {
 	for _, hook := range hm.postLoad {
+		if report != nil {
+			report.Hooks = append(report.Hooks, hook.Name())
+		}
 		if err := hook.OnPostLoad(c, data); err != nil {
 			return fmt.Errorf("post-load hook %s: %w", hook.Name(), err)
 		}
+		if provenance == nil {
+			continue
+		}
+		for k := range data {
+			if _, ok := provenance[k]; !ok {
+				provenance[k] = "hook:" + hook.Name()
+			}
+		}
 	}
 	return nil
 }
@@ -117,6 +161,17 @@ func (hm *HookManager) ExecutePostBind(c *Config, dst any) error {
 	return nil
 }
 
+// closers returns the registered hooks implementing io.Closer.
+func (hm *HookManager) closers() []io.Closer {
+	var out []io.Closer
+	for _, h := range hm.all {
+		if closer, ok := h.(io.Closer); ok {
+			out = append(out, closer)
+		}
+	}
+	return out
+}
+
 // sortHooks is a generic hook sorter using interface constraints.
 func sortHooks[T Hook](hooks []T) {
 	for i := 1; i < len(hooks); i++ {
@@ -203,7 +258,7 @@ func (h *DefaultsHook) OnPostLoad(_ *Config, data map[string]any) error {
 // =============================================================================
 
 // SourceMiddleware wraps a source with additional behavior.
-type SourceMiddleware func(Source) Source
+type SourceMiddleware = source.Middleware
 
 // WithTemplate wraps a source with template processing.
 func WithTemplate(processor *TemplateProcessor) SourceMiddleware {
@@ -221,176 +276,89 @@ func WithEncryption(processor *EncryptionProcessor) SourceMiddleware {
 
 // WithCaching wraps a source with caching.
 func WithCaching(ttl time.Duration) SourceMiddleware {
-	return func(src Source) Source {
-		return NewCachedSource(src, ttl)
-	}
+	return middleware.WithCaching(ttl)
 }
 
 // WithRetry wraps a source with retry logic.
 func WithRetry(maxAttempts int, backoff time.Duration) SourceMiddleware {
+	return middleware.WithRetry(maxAttempts, backoff)
+}
+
+// WithSharedLoading routes loads through a SharedLoader so identical sources
+// across Config instances are read and parsed once.
+func WithSharedLoading(loader *SharedLoader) SourceMiddleware {
 	return func(src Source) Source {
-		return NewRetrySource(src, maxAttempts, backoff)
+		return NewSharedSource(src, loader)
 	}
 }
 
+// WithChaos wraps a source with random failures and latency for resilience
+// testing. failureProbability is in [0, 1]; each load is delayed by a random
+// duration up to maxLatency.
+func WithChaos(failureProbability float64, maxLatency time.Duration) SourceMiddleware {
+	return middleware.WithChaos(failureProbability, maxLatency)
+}
+
 // ChainMiddleware chains multiple middleware functions.
-func ChainMiddleware(middleware ...SourceMiddleware) SourceMiddleware {
-	return func(src Source) Source {
-		for i := len(middleware) - 1; i >= 0; i-- {
-			src = middleware[i](src)
-		}
-		return src
-	}
+func ChainMiddleware(mw ...SourceMiddleware) SourceMiddleware {
+	return source.Chain(mw...)
 }
 
 // =============================================================================
-// Middleware Implementations
+// Middleware Implementations (re-exported from the middleware package)
 // =============================================================================
 
 // CachedSource caches the result of a source for a specified duration.
-type CachedSource struct {
-	BaseSource
-	source   Source
-	cache    map[string]any
-	cachedAt time.Time
-	ttl      time.Duration
-}
+type CachedSource = middleware.Cached
 
 func NewCachedSource(source Source, ttl time.Duration) *CachedSource {
-	return &CachedSource{
-		BaseSource: NewBaseSource("cached:"+source.Name(), source.Priority()),
-		source:     source,
-		cache:      nil,
-		ttl:        ttl,
-	}
-}
-
-func (s *CachedSource) Load() (map[string]any, error) {
-	if s.cache != nil && time.Since(s.cachedAt) < s.ttl {
-		return cloneMap(s.cache), nil
-	}
-
-	data, err := s.source.Load()
-	if err != nil {
-		return nil, err
-	}
-
-	s.cache = cloneMap(data)
-	s.cachedAt = time.Now()
-	return data, nil
-}
-
-func (s *CachedSource) WatchPaths() []string {
-	return s.source.WatchPaths()
+	return middleware.NewCached(source, ttl)
 }
 
 // RetrySource retries failed loads with exponential backoff.
-type RetrySource struct {
-	BaseSource
-	source      Source
-	maxAttempts int
-	backoff     time.Duration
-}
+type RetrySource = middleware.Retry
 
 func NewRetrySource(source Source, maxAttempts int, backoff time.Duration) *RetrySource {
-	return &RetrySource{
-		BaseSource:  NewBaseSource("retry:"+source.Name(), source.Priority()),
-		source:      source,
-		maxAttempts: maxAttempts,
-		backoff:     backoff,
-	}
+	return middleware.NewRetry(source, maxAttempts, backoff)
 }
 
-func (s *RetrySource) Load() (map[string]any, error) {
-	var lastErr error
-	for attempt := 0; attempt < s.maxAttempts; attempt++ {
-		data, err := s.source.Load()
-		if err == nil {
-			return data, nil
-		}
-		lastErr = err
-		if attempt < s.maxAttempts-1 {
-			time.Sleep(s.backoff * time.Duration(attempt+1))
-		}
-	}
-	return nil, fmt.Errorf("failed after %d attempts: %w", s.maxAttempts, lastErr)
-}
+// ErrChaosInjected is returned by ChaosSource for injected failures.
+var ErrChaosInjected = middleware.ErrChaosInjected
 
-func (s *RetrySource) WatchPaths() []string {
-	return s.source.WatchPaths()
-}
+// ChaosSource randomly delays and fails loads of the wrapped source.
+type ChaosSource = middleware.Chaos
 
-// =============================================================================
-// Composite Source
-// =============================================================================
+func NewChaosSource(source Source, failureProbability float64, maxLatency time.Duration) *ChaosSource {
+	return middleware.NewChaos(source, failureProbability, maxLatency)
+}
 
 // CompositeSource merges multiple sources as a single logical source.
-type CompositeSource struct {
-	BaseSource
-	sources []Source
-}
+type CompositeSource = middleware.Composite
 
 func NewCompositeSource(name string, priority int, sources ...Source) *CompositeSource {
-	return &CompositeSource{
-		BaseSource: NewBaseSource(name, priority),
-		sources:    sources,
-	}
+	return middleware.NewComposite(name, priority, sources...)
 }
 
-func (s *CompositeSource) Load() (map[string]any, error) {
-	merged := make(map[string]any)
-	for _, src := range s.sources {
-		data, err := src.Load()
-		if err != nil {
-			return nil, fmt.Errorf("composite source %s: %w", src.Name(), err)
-		}
-		deepMerge(merged, data)
-	}
-	return merged, nil
-}
+// ConflictPolicy decides how a CompositeSource resolves conflicting keys.
+type ConflictPolicy = middleware.ConflictPolicy
 
-func (s *CompositeSource) WatchPaths() []string {
-	var paths []string
-	for _, src := range s.sources {
-		paths = append(paths, src.WatchPaths()...)
-	}
-	return paths
-}
+const (
+	ConflictLastWins  = middleware.LastWins
+	ConflictFirstWins = middleware.FirstWins
+	ConflictError     = middleware.ErrorOnConflict
+	ConflictReport    = middleware.ReportConflicts
+)
 
-// AddSource adds a source to the composite.
-func (s *CompositeSource) AddSource(src Source) {
-	s.sources = append(s.sources, src)
-}
+// SourceConflict is a key supplied with different values by several
+// children of a CompositeSource.
+type SourceConflict = middleware.Conflict
 
-// =============================================================================
-// Conditional Source
-// =============================================================================
+// SourceConflictError is returned by a CompositeSource using ConflictError.
+type SourceConflictError = middleware.ConflictError
 
 // ConditionalSource loads data conditionally based on a predicate.
-type ConditionalSource struct {
-	BaseSource
-	source    Source
-	condition func() bool
-}
+type ConditionalSource = middleware.Conditional
 
 func NewConditionalSource(source Source, condition func() bool) *ConditionalSource {
-	return &ConditionalSource{
-		BaseSource: NewBaseSource("conditional:"+source.Name(), source.Priority()),
-		source:     source,
-		condition:  condition,
-	}
-}
-
-func (s *ConditionalSource) Load() (map[string]any, error) {
-	if !s.condition() {
-		return make(map[string]any), nil
-	}
-	return s.source.Load()
-}
-
-func (s *ConditionalSource) WatchPaths() []string {
-	if s.condition() {
-		return s.source.WatchPaths()
-	}
-	return nil
+	return middleware.NewConditional(source, condition)
 }
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// HTTP Client Configuration
+// =============================================================================
+
+// HTTPClientConfig describes how remote sources reach their backends, for
+// networks that require a proxy, a private CA or client certificates.
+type HTTPClientConfig struct {
+	// Proxy is the proxy URL. Empty uses HTTPS_PROXY, HTTP_PROXY and
+	// NO_PROXY from the environment; "direct" disables proxying.
+	Proxy string
+
+	CAFile   string // PEM bundle trusted in addition to the system roots
+	CertFile string // client certificate, re-read when it changes
+	KeyFile  string
+
+	Timeout               time.Duration // whole request, defaults to the source's timeout
+	DialTimeout           time.Duration // defaults to 30s
+	TLSHandshakeTimeout   time.Duration // defaults to 10s
+	ResponseHeaderTimeout time.Duration // no limit by default
+
+	UserAgent string // sent when a request sets none
+}
+
+// NewHTTPClient builds a client from cfg. Pass it as a source's Client to
+// configure that source alone, or use SetDefaultHTTPClient for all of them.
+func NewHTTPClient(cfg HTTPClientConfig) (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+
+	switch cfg.Proxy {
+	case "":
+		tr.Proxy = http.ProxyFromEnvironment
+	case "direct":
+		tr.Proxy = nil
+	default:
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("http client: invalid proxy %q", cfg.Proxy)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if cfg.DialTimeout > 0 {
+		tr.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if cfg.TLSHandshakeTimeout > 0 {
+		tr.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+	tr.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+
+	tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("http client: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("http client: no certificates in %s", cfg.CAFile)
+		}
+		tr.TLSClientConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("http client: CertFile and KeyFile must be set together")
+		}
+		if err := MutualTLS(cfg.CertFile, cfg.KeyFile).ConfigureTLS(tr.TLSClientConfig); err != nil {
+			return nil, fmt.Errorf("http client: %w", err)
+		}
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout, Transport: tr}
+	if cfg.UserAgent != "" {
+		client.Transport = &authTransport{base: tr, auth: userAgent(cfg.UserAgent)}
+	}
+	return client, nil
+}
+
+func userAgent(ua string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		if req.Header.Get("User-Agent") == "" {
+			req.Header.Set("User-Agent", ua)
+		}
+		return nil
+	})
+}
+
+var defaultHTTP struct {
+	mu     sync.RWMutex
+	client *http.Client
+}
+
+// SetDefaultHTTPClient configures the client used by remote sources,
+// OAuth2 token requests and webhooks created afterwards without a Client
+// of their own. Cloud credential providers keep their own clients, as
+// instance metadata endpoints must not go through a proxy.
+func SetDefaultHTTPClient(cfg HTTPClientConfig) error {
+	client, err := NewHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+	defaultHTTP.mu.Lock()
+	defaultHTTP.client = client
+	defaultHTTP.mu.Unlock()
+	return nil
+}
+
+// defaultHTTPClient returns a client using the configured default
+// transport, with timeout unless the default sets its own.
+func defaultHTTPClient(timeout time.Duration) *http.Client {
+	defaultHTTP.mu.RLock()
+	defer defaultHTTP.mu.RUnlock()
+
+	if defaultHTTP.client == nil {
+		return &http.Client{Timeout: timeout}
+	}
+	client := *defaultHTTP.client
+	if client.Timeout == 0 {
+		client.Timeout = timeout
+	}
+	return &client
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// =============================================================================
+// Key Constants Generation
+// =============================================================================
+
+// ScanKeys returns the sorted leaf keys declared by config files and by the
+// config tags of structs. Files are decoded with the registered decoders;
+// list elements ("servers.0.host") are skipped in favour of the list key.
+// Structs may be values, pointers or reflect.Types.
+func ScanKeys(files []string, structs ...any) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, path := range files {
+		data, err := File(path).Load()
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", path, err)
+		}
+		for k := range data {
+			if !hasIndexSegment(k) {
+				seen[k] = true
+			}
+		}
+	}
+	for _, s := range structs {
+		typ, ok := s.(reflect.Type)
+		if !ok {
+			typ = reflect.TypeOf(s)
+		}
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("scan %v: not a struct", typ)
+		}
+		structKeys("", typ, seen)
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func structKeys(prefix string, typ reflect.Type, out map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := configKeyName(sf)
+		if name == "-" {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if sf.Anonymous && sf.Tag.Get("config") == "" && sf.Tag.Get("json") == "" {
+				structKeys(prefix, ft, out)
+			} else {
+				structKeys(joinKeys(prefix, name), ft, out)
+			}
+			continue
+		}
+		out[joinKeys(prefix, name)] = true
+	}
+}
+
+func hasIndexSegment(key string) bool {
+	parts := splitPath(key)
+	for _, p := range parts[1:] {
+		if _, err := strconv.Atoi(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyGenOptions configure GenerateKeys.
+type KeyGenOptions struct {
+	Package string   // package of the generated files, defaults to "config"
+	Prefix  string   // constant name prefix, defaults to "Key"
+	Files   []string // config files to scan; paths are also used by the generated test
+	Structs []any    // structs whose config tags declare keys
+	Keys    []string // additional keys
+}
+
+// GenerateKeys returns a Go file declaring a constant per key
+// (KeyServerPort = "server.port") and a list of all keys, plus a test that
+// fails when a file gains a key without a constant or loses one that has a
+// constant. Run it from go:generate in the target package so the file
+// paths in the test resolve.
+func GenerateKeys(opts KeyGenOptions) (code, test []byte, err error) {
+	if opts.Package == "" {
+		opts.Package = "config"
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "Key"
+	}
+
+	fileKeys, err := ScanKeys(opts.Files)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, err := ScanKeys(nil, opts.Structs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys = uniqueSorted(append(append(keys, fileKeys...), opts.Keys...))
+
+	names := make(map[string]string, len(keys))
+	for _, k := range keys {
+		name := opts.Prefix + keyIdentifier(k)
+		if other, dup := names[name]; dup {
+			return nil, nil, fmt.Errorf("keys %q and %q both map to %s", other, k, name)
+		}
+		names[name] = k
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by go-config; DO NOT EDIT.\n\npackage %s\n\n", opts.Package)
+	b.WriteString("// Configuration keys.\nconst (\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s%s = %q\n", opts.Prefix, keyIdentifier(k), k)
+	}
+	fmt.Fprintf(&b, ")\n\n// %ss lists every configuration key.\nvar %ss = []string{\n", opts.Prefix, opts.Prefix)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s%s,\n", opts.Prefix, keyIdentifier(k))
+	}
+	b.WriteString("}\n")
+	if code, err = format.Source(b.Bytes()); err != nil {
+		return nil, nil, fmt.Errorf("format keys: %w", err)
+	}
+
+	b.Reset()
+	fmt.Fprintf(&b, "// Code generated by go-config; DO NOT EDIT.\n\npackage %s\n\n", opts.Package)
+	b.WriteString("import (\n\"slices\"\n\"testing\"\n\nconfig \"github.com/os-golib/go-config\"\n)\n\n")
+	fmt.Fprintf(&b, "func Test%ssComplete(t *testing.T) {\n", opts.Prefix)
+	fmt.Fprintf(&b, "files := %#v\n", opts.Files)
+	fmt.Fprintf(&b, "generated := %#v\n", fileKeys)
+	b.WriteString(`current, err := config.ScanKeys(files)
+if err != nil {
+	t.Fatal(err)
+}
+for _, k := range current {
+	if !slices.Contains(generated, k) {
+		t.Errorf("key %q has no constant; rerun go generate", k)
+	}
+}
+for _, k := range generated {
+	if !slices.Contains(current, k) {
+		t.Errorf("key %q no longer exists in %v; rerun go generate", k, files)
+	}
+}
+}
+`)
+	if test, err = format.Source(b.Bytes()); err != nil {
+		return nil, nil, fmt.Errorf("format keys test: %w", err)
+	}
+	return code, test, nil
+}
+
+// WriteKeys generates keys into path and the test into path's _test.go
+// sibling.
+func WriteKeys(opts KeyGenOptions, path string) error {
+	code, test, err := GenerateKeys(opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, code, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(path, ".go")+"_test.go", test, 0o644)
+}
+
+// keyIdentifier turns "server.max_conns" into "ServerMaxConns".
+func keyIdentifier(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func uniqueSorted(keys []string) []string {
+	sort.Strings(keys)
+	out := keys[:0]
+	for i, k := range keys {
+		if k != "" && (i == 0 || k != keys[i-1]) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Azure Key Vault Source
+// =============================================================================
+
+// KeyVaultOptions configure an Azure Key Vault secrets source.
+type KeyVaultOptions struct {
+	VaultURL string   // e.g. https://myvault.vault.azure.net
+	Prefix   string   // key namespace for the secrets, e.g. "secrets"
+	Names    []string // secrets to load; empty loads every enabled secret
+
+	RefreshInterval time.Duration // minimum time between polls, defaults to 5m
+
+	Credentials AzureTokenProvider // defaults to ManagedIdentity("")
+	Auth        AuthProvider       // replaces the default Credentials when set
+	Client      *http.Client
+}
+
+// KeyVaultSource loads secrets from Azure Key Vault. Secret names map to
+// keys with "--" as the separator ("db--password" becomes "db.password"),
+// under Prefix when set. Poll lists the vault and reloads when any secret
+// was updated, added or removed.
+type KeyVaultSource struct {
+	BaseSource
+	opts KeyVaultOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	versions map[string]int64 // secret name -> updated timestamp
+	fetched  bool
+	nextPoll time.Time
+}
+
+func KeyVault(opts KeyVaultOptions) *KeyVaultSource {
+	return KeyVaultWithPriority(opts, DefaultRemotePriority)
+}
+
+func KeyVaultWithPriority(opts KeyVaultOptions, priority int) *KeyVaultSource {
+	opts.VaultURL = strings.TrimRight(opts.VaultURL, "/")
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 5 * time.Minute
+	}
+	if opts.Credentials == nil && opts.Auth == nil {
+		opts.Credentials = ManagedIdentity("")
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+
+	return &KeyVaultSource{
+		BaseSource: NewBaseSource("keyvault:"+opts.VaultURL, priority),
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *KeyVaultSource) Remote() bool { return true }
+
+// Load returns the secrets, fetching them on first use.
+func (s *KeyVaultSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether any secret changed since the last fetch.
+func (s *KeyVaultSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch(context.Background())
+}
+
+// SecretKeys returns the keys of the last fetch, so a Config treats them as
+// secret (see SecretKeySource).
+func (s *KeyVaultSource) SecretKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return mapKeys(s.data)
+}
+
+// fetch lists the vault and downloads secret values when the listing
+// differs from the last one.
+func (s *KeyVaultSource) fetch(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	versions, err := s.list(ctx)
+	if err != nil {
+		return false, err
+	}
+	if s.fetched && maps.Equal(versions, s.versions) {
+		return false, nil
+	}
+
+	data := make(map[string]any, len(versions))
+	for name := range versions {
+		value, err := s.secret(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		key := strings.ReplaceAll(name, "--", ".")
+		data[joinKeys(s.opts.Prefix, key)] = value
+	}
+
+	s.data, s.versions = data, versions
+	s.fetched = true
+	return true, nil
+}
+
+// list returns enabled secrets and their last update time.
+func (s *KeyVaultSource) list(ctx context.Context) (map[string]int64, error) {
+	wanted := make(map[string]bool, len(s.opts.Names))
+	for _, name := range s.opts.Names {
+		wanted[name] = true
+	}
+
+	versions := make(map[string]int64)
+	next := s.opts.VaultURL + "/secrets?api-version=7.4"
+	for next != "" {
+		resp, body, err := azureRequest(ctx, s.opts.Client, s.opts.Credentials, s.resource(), next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("key vault: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("key vault: list secrets: %s: %s", resp.Status, body)
+		}
+
+		var page struct {
+			Value []struct {
+				ID         string `json:"id"`
+				Attributes struct {
+					Enabled bool  `json:"enabled"`
+					Updated int64 `json:"updated"`
+				} `json:"attributes"`
+			} `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("key vault: decode secrets: %w", err)
+		}
+
+		for _, item := range page.Value {
+			name := path.Base(item.ID)
+			if !item.Attributes.Enabled || (len(wanted) > 0 && !wanted[name]) {
+				continue
+			}
+			versions[name] = item.Attributes.Updated
+		}
+		next = page.NextLink
+	}
+	return versions, nil
+}
+
+func (s *KeyVaultSource) secret(ctx context.Context, name string) (string, error) {
+	endpoint := s.opts.VaultURL + "/secrets/" + url.PathEscape(name) + "?api-version=7.4"
+	resp, body, err := azureRequest(ctx, s.opts.Client, s.opts.Credentials, s.resource(), endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("key vault: secret %q: %w", name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("key vault: secret %q: %s", name, resp.Status)
+	}
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("key vault: decode secret %q: %w", name, err)
+	}
+	return out.Value, nil
+}
+
+// resource is the token audience: the vault's DNS suffix, which differs
+// between Azure clouds (vault.azure.net, vault.azure.cn, ...).
+func (s *KeyVaultSource) resource() string {
+	u, err := url.Parse(s.opts.VaultURL)
+	if err != nil {
+		return "https://vault.azure.net"
+	}
+	_, suffix, ok := strings.Cut(u.Host, ".")
+	if !ok {
+		return "https://vault.azure.net"
+	}
+	return u.Scheme + "://" + suffix
+}
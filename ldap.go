@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// LDAP Source
+// =============================================================================
+
+// LDAPEntry is a directory entry returned by a search.
+type LDAPEntry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// LDAPSearcher runs a subtree search. Adapt your LDAP client (for example
+// github.com/go-ldap/ldap) to this interface; the source does not bind or
+// manage connections itself.
+type LDAPSearcher interface {
+	Search(baseDN, filter string, attributes []string) ([]LDAPEntry, error)
+}
+
+// LDAPSearchFunc adapts a function to LDAPSearcher.
+type LDAPSearchFunc func(baseDN, filter string, attributes []string) ([]LDAPEntry, error)
+
+func (f LDAPSearchFunc) Search(baseDN, filter string, attributes []string) ([]LDAPEntry, error) {
+	return f(baseDN, filter, attributes)
+}
+
+// LDAPOptions configure an LDAP subtree source.
+type LDAPOptions struct {
+	BaseDN     string   // e.g. "ou=myapp,ou=apps,dc=example,dc=com"
+	Filter     string   // defaults to "(objectClass=*)"
+	Attributes []string // attributes to read; empty reads all but objectClass
+	Prefix     string   // key namespace for the subtree
+
+	RefreshInterval time.Duration // minimum time between polls, defaults to 1m
+}
+
+// LDAPSource maps a directory subtree to configuration keys. Each entry's
+// RDN values below BaseDN form the key path, outermost first, and its
+// attributes become leaf keys: the attribute "host" on
+// "cn=primary,cn=db,<BaseDN>" is loaded as "db.primary.host". Multi-valued
+// attributes become lists. Attribute names are lowercased.
+type LDAPSource struct {
+	BaseSource
+	searcher LDAPSearcher
+	opts     LDAPOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	fetched  bool
+	nextPoll time.Time
+}
+
+func LDAP(searcher LDAPSearcher, opts LDAPOptions) *LDAPSource {
+	return LDAPWithPriority(searcher, opts, DefaultRemotePriority)
+}
+
+func LDAPWithPriority(searcher LDAPSearcher, opts LDAPOptions, priority int) *LDAPSource {
+	if opts.Filter == "" {
+		opts.Filter = "(objectClass=*)"
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	return &LDAPSource{
+		BaseSource: NewBaseSource("ldap:"+opts.BaseDN, priority),
+		searcher:   searcher,
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *LDAPSource) Remote() bool { return true }
+
+// Load returns the subtree's attributes, searching on first use.
+func (s *LDAPSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll searches the subtree again once the refresh interval has elapsed
+// and reports whether any attribute changed.
+func (s *LDAPSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch()
+}
+
+func (s *LDAPSource) fetch() (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	entries, err := s.searcher.Search(s.opts.BaseDN, s.opts.Filter, s.opts.Attributes)
+	if err != nil {
+		return false, fmt.Errorf("ldap search: %w", err)
+	}
+
+	base := parseDN(s.opts.BaseDN)
+	data := make(map[string]any)
+	for _, entry := range entries {
+		rdns := parseDN(entry.DN)
+		if len(rdns) < len(base) || !slices.EqualFunc(rdns[len(rdns)-len(base):], base, strings.EqualFold) {
+			continue
+		}
+
+		key := s.opts.Prefix
+		for i := len(rdns) - len(base) - 1; i >= 0; i-- {
+			_, value, _ := strings.Cut(rdns[i], "=")
+			key = joinKeys(key, value)
+		}
+
+		for name, values := range entry.Attributes {
+			name = strings.ToLower(name)
+			if name == "objectclass" && len(s.opts.Attributes) == 0 {
+				continue
+			}
+			attrKey := joinKeys(key, name)
+			if len(values) == 1 {
+				data[attrKey] = values[0]
+				continue
+			}
+			list := make([]any, len(values))
+			for i, v := range values {
+				list[i] = v
+			}
+			flatten(attrKey, list, data)
+		}
+	}
+
+	changed := !s.fetched || !bytes.Equal(canonicalBytes(data), canonicalBytes(s.data))
+	s.data = data
+	s.fetched = true
+	return changed, nil
+}
+
+// parseDN splits a DN into normalized RDNs ("cn=db"), honouring backslash
+// escapes. Attribute types are lowercased; multi-valued RDNs keep their
+// first component.
+func parseDN(dn string) []string {
+	var rdns []string
+	var cur strings.Builder
+	escaped, skip := false, false
+
+	flush := func() {
+		rdn := strings.TrimSpace(cur.String())
+		cur.Reset()
+		skip = false
+		if rdn == "" {
+			return
+		}
+		attr, value, _ := strings.Cut(rdn, "=")
+		rdns = append(rdns, strings.ToLower(strings.TrimSpace(attr))+"="+strings.TrimSpace(value))
+	}
+
+	for _, r := range dn {
+		switch {
+		case escaped:
+			if !skip {
+				cur.WriteRune(r)
+			}
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			flush()
+		case r == '+':
+			skip = true
+		case !skip:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return rdns
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// Size Limits
+// =============================================================================
+
+// Limits bound what a load may ingest, protecting a service from a huge or
+// crafted payload, typically from a remote source. Zero fields are not
+// enforced.
+type Limits struct {
+	MaxKeys       int // keys in the merged config, and in each source
+	MaxValueBytes int // size of a single value; lists and maps count their contents
+	MaxDepth      int // key segments ("a.b.c" is 3), including maps nested in values
+}
+
+// ErrLimitExceeded is wrapped by every LimitError.
+var ErrLimitExceeded = errors.New("config limit exceeded")
+
+// LimitError reports a value, key or key count over a limit.
+type LimitError struct {
+	Limit  string // "keys", "value size" or "depth"
+	Key    string // offending key; empty for key counts
+	Actual int
+	Max    int
+}
+
+func (e *LimitError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%d %s, more than the limit of %d", e.Actual, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("key %q: %s %d exceeds the limit of %d", e.Key, e.Limit, e.Actual, e.Max)
+}
+
+func (e *LimitError) Unwrap() error { return ErrLimitExceeded }
+
+// WithLimits makes Load reject sources and merged configs exceeding limits.
+func WithLimits(limits Limits) Option {
+	return func(c *Config) {
+		c.limits = limits
+	}
+}
+
+// check returns a *LimitError for the first limit data exceeds.
+func (l Limits) check(data map[string]any) error {
+	if l == (Limits{}) {
+		return nil
+	}
+	keys := 0
+	var err error
+	walkValues("", data, 0, func(key string, v any, depth int) bool {
+		keys++
+		switch {
+		case l.MaxKeys > 0 && keys > l.MaxKeys:
+			err = &LimitError{Limit: "keys", Actual: countLeaves(data), Max: l.MaxKeys}
+		case l.MaxDepth > 0 && depth > l.MaxDepth:
+			err = &LimitError{Limit: "depth", Key: key, Actual: depth, Max: l.MaxDepth}
+		case l.MaxValueBytes > 0:
+			if size := valueSize(v); size > l.MaxValueBytes {
+				err = &LimitError{Limit: "value size", Key: key, Actual: size, Max: l.MaxValueBytes}
+			}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// walkValues calls fn for each leaf value with its full key and depth,
+// descending into nested maps, until fn returns false.
+func walkValues(prefix string, data map[string]any, depth int, fn func(key string, v any, depth int) bool) bool {
+	for k, v := range data {
+		key := joinKeys(prefix, k)
+		d := depth + strings.Count(k, ".") + 1
+		if m, ok := v.(map[string]any); ok && len(m) > 0 {
+			if !walkValues(key, m, d, fn) {
+				return false
+			}
+			continue
+		}
+		if !fn(key, v, d) {
+			return false
+		}
+	}
+	return true
+}
+
+func countLeaves(data map[string]any) int {
+	n := 0
+	walkValues("", data, 0, func(string, any, int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// valueSize approximates a value's size in bytes.
+func valueSize(v any) int {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(x)
+	case []byte:
+		return len(x)
+	case []any:
+		n := 0
+		for _, e := range x {
+			n += valueSize(e)
+		}
+		return n
+	case map[string]any:
+		n := 0
+		for k, e := range x {
+			n += len(k) + valueSize(e)
+		}
+		return n
+	default:
+		return len(fmt.Sprint(x))
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Prometheus Metrics
+// =============================================================================
+
+// MetricSample is a single gauge sample.
+type MetricSample struct {
+	Name   string
+	Help   string
+	Labels map[string]string
+	Value  float64
+}
+
+// MetricsExporter exposes selected numeric and boolean config values as
+// Prometheus gauges, along with the last reload time and a config hash:
+//
+//	config_value{key="server.port"} 8080
+//	config_last_reload_timestamp_seconds 1.7e9
+//	config_hash 2.1e9
+//
+// It serves the text exposition format directly, so no client library is
+// required. To register with a prometheus.Registry instead, adapt Collect
+// in a custom Collector.
+type MetricsExporter struct {
+	config    *Config
+	patterns  []string
+	namespace string
+}
+
+// NewMetricsExporter exports keys matching any of the given key patterns
+// (see Rules key patterns, e.g. "server.*"). With no patterns every
+// numeric or boolean key is exported. Secret and PII keys are never
+// exported, even when a pattern matches them.
+func NewMetricsExporter(c *Config, keyPatterns ...string) *MetricsExporter {
+	return &MetricsExporter{config: c, patterns: keyPatterns, namespace: "config"}
+}
+
+// WithNamespace sets the metric name prefix (default "config").
+func (e *MetricsExporter) WithNamespace(ns string) *MetricsExporter {
+	e.namespace = ns
+	return e
+}
+
+// Collect returns the current samples.
+func (e *MetricsExporter) Collect() []MetricSample {
+	e.config.mu.RLock()
+	data := cloneMap(e.config.data)
+	loadedAt := e.config.loadedAt
+	hidden := make(map[string]bool)
+	for key := range data {
+		if e.config.isSecret(key) || e.config.isPII(key) {
+			hidden[key] = true
+		}
+	}
+	e.config.mu.RUnlock()
+
+	keys := mapKeys(data)
+	sort.Strings(keys)
+
+	samples := make([]MetricSample, 0, len(keys)+2)
+	for _, key := range keys {
+		if hidden[key] || !e.selected(key) {
+			continue
+		}
+		if v, ok := metricValue(data[key]); ok {
+			samples = append(samples, MetricSample{
+				Name:   e.namespace + "_value",
+				Help:   "Numeric or boolean configuration value.",
+				Labels: map[string]string{"key": key},
+				Value:  v,
+			})
+		}
+	}
+
+	var reload float64
+	if !loadedAt.IsZero() {
+		reload = float64(loadedAt.UnixNano()) / 1e9
+	}
+	h := fnv.New32a()
+	h.Write(canonicalBytes(data))
+
+	return append(samples,
+		MetricSample{
+			Name:  e.namespace + "_last_reload_timestamp_seconds",
+			Help:  "Unix time of the last successful configuration load.",
+			Value: reload,
+		},
+		MetricSample{
+			Name:  e.namespace + "_hash",
+			Help:  "FNV-32a hash of the current configuration.",
+			Value: float64(h.Sum32()),
+		},
+	)
+}
+
+// WriteTo writes the samples in the Prometheus text exposition format.
+func (e *MetricsExporter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	described := make(map[string]bool)
+	for _, s := range e.Collect() {
+		if !described[s.Name] {
+			described[s.Name] = true
+			fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s gauge\n", s.Name, s.Help, s.Name)
+		}
+		buf.WriteString(s.Name)
+		if len(s.Labels) > 0 {
+			buf.WriteString(formatLabels(s.Labels))
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(s.Value, 'g', -1, 64))
+		buf.WriteByte('\n')
+	}
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics, so the exporter can be mounted on /metrics.
+func (e *MetricsExporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = e.WriteTo(w)
+}
+
+func (e *MetricsExporter) selected(key string) bool {
+	if len(e.patterns) == 0 {
+		return true
+	}
+	for _, p := range e.patterns {
+		if p == key || matchKeyPattern(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// metricValue converts numbers, booleans, and numeric strings to a float.
+func metricValue(v any) (float64, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		f, err := strconv.ParseFloat(fmt.Sprint(x), 64)
+		return f, err == nil
+	case string:
+		if x == "true" || x == "false" {
+			return metricValue(x == "true")
+		}
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + `="` + labelEscaper.Replace(labels[name]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// labelEscaper escapes label values as the Prometheus text format
+// requires: only backslash, double quote and newline.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
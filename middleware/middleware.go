@@ -0,0 +1,398 @@
+// Package middleware provides source wrappers for caching, retries, fault
+// injection, composition and conditional loading. It depends only on the
+// source package.
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/os-golib/go-config/source"
+)
+
+// =============================================================================
+// Middleware Constructors
+// =============================================================================
+
+// WithCaching wraps a source with caching.
+func WithCaching(ttl time.Duration) source.Middleware {
+	return func(src source.Source) source.Source {
+		return NewCached(src, ttl)
+	}
+}
+
+// WithRetry wraps a source with retry logic.
+func WithRetry(maxAttempts int, backoff time.Duration) source.Middleware {
+	return func(src source.Source) source.Source {
+		return NewRetry(src, maxAttempts, backoff)
+	}
+}
+
+// WithChaos wraps a source with random failures and latency for resilience
+// testing. failureProbability is in [0, 1]; each load is delayed by a random
+// duration up to maxLatency.
+func WithChaos(failureProbability float64, maxLatency time.Duration) source.Middleware {
+	return func(src source.Source) source.Source {
+		return NewChaos(src, failureProbability, maxLatency)
+	}
+}
+
+// =============================================================================
+// Cached Source
+// =============================================================================
+
+// Cached caches the result of a source for a specified duration.
+type Cached struct {
+	source.Base
+	source   source.Source
+	cache    map[string]any
+	cachedAt time.Time
+	ttl      time.Duration
+}
+
+func NewCached(src source.Source, ttl time.Duration) *Cached {
+	return &Cached{
+		Base:   source.NewBase("cached:"+src.Name(), src.Priority()),
+		source: src,
+		cache:  nil,
+		ttl:    ttl,
+	}
+}
+
+func (s *Cached) Load() (map[string]any, error) {
+	if s.cache != nil && time.Since(s.cachedAt) < s.ttl {
+		return maps.Clone(s.cache), nil
+	}
+
+	data, err := s.source.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache = maps.Clone(data)
+	s.cachedAt = time.Now()
+	return data, nil
+}
+
+// Unwrap returns the wrapped source.
+func (s *Cached) Unwrap() source.Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *Cached) Priority() int { return s.source.Priority() }
+
+func (s *Cached) WatchPaths() []string {
+	return s.source.WatchPaths()
+}
+
+// =============================================================================
+// Retry Source
+// =============================================================================
+
+// Retry retries failed loads with exponential backoff.
+type Retry struct {
+	source.Base
+	source      source.Source
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func NewRetry(src source.Source, maxAttempts int, backoff time.Duration) *Retry {
+	return &Retry{
+		Base:        source.NewBase("retry:"+src.Name(), src.Priority()),
+		source:      src,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+func (s *Retry) Load() (map[string]any, error) {
+	var lastErr error
+	for attempt := 0; attempt < s.maxAttempts; attempt++ {
+		data, err := s.source.Load()
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+		if attempt < s.maxAttempts-1 {
+			time.Sleep(s.backoff * time.Duration(attempt+1))
+		}
+	}
+	return nil, fmt.Errorf("failed after %d attempts: %w", s.maxAttempts, lastErr)
+}
+
+// Unwrap returns the wrapped source.
+func (s *Retry) Unwrap() source.Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *Retry) Priority() int { return s.source.Priority() }
+
+func (s *Retry) WatchPaths() []string {
+	return s.source.WatchPaths()
+}
+
+// =============================================================================
+// Chaos Source
+// =============================================================================
+
+// ErrChaosInjected is returned by Chaos for injected failures.
+var ErrChaosInjected = errors.New("chaos: injected failure")
+
+// Chaos randomly delays and fails loads of the wrapped source.
+type Chaos struct {
+	source.Base
+	source      source.Source
+	probability float64
+	maxLatency  time.Duration
+}
+
+func NewChaos(src source.Source, failureProbability float64, maxLatency time.Duration) *Chaos {
+	return &Chaos{
+		Base:        source.NewBase("chaos:"+src.Name(), src.Priority()),
+		source:      src,
+		probability: failureProbability,
+		maxLatency:  maxLatency,
+	}
+}
+
+func (s *Chaos) Load() (map[string]any, error) {
+	if s.maxLatency > 0 {
+		time.Sleep(rand.N(s.maxLatency))
+	}
+	if rand.Float64() < s.probability {
+		return nil, fmt.Errorf("%s: %w", s.source.Name(), ErrChaosInjected)
+	}
+	return s.source.Load()
+}
+
+// Unwrap returns the wrapped source.
+func (s *Chaos) Unwrap() source.Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *Chaos) Priority() int { return s.source.Priority() }
+
+func (s *Chaos) WatchPaths() []string {
+	return s.source.WatchPaths()
+}
+
+// =============================================================================
+// Composite Source
+// =============================================================================
+
+// ConflictPolicy decides how Composite resolves a key that more than one
+// child supplies with different values.
+type ConflictPolicy int
+
+const (
+	LastWins        ConflictPolicy = iota // later children override earlier ones (default)
+	FirstWins                             // the first child supplying a key keeps it
+	ErrorOnConflict                       // the load fails with a *ConflictError
+	ReportConflicts                       // last wins; conflicts are kept for Conflicts
+)
+
+// Conflict is a key supplied with different values by several children.
+type Conflict struct {
+	Key     string
+	Sources []string // children supplying the key, in load order
+	Values  []any    // their values, matching Sources
+	Winner  string   // child whose value was kept
+}
+
+// ConflictError is returned by a Composite using ErrorOnConflict.
+type ConflictError struct {
+	Composite string
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	keys := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		keys[i] = fmt.Sprintf("%s (%s)", c.Key, strings.Join(c.Sources, ", "))
+	}
+	return fmt.Sprintf("composite source %s: conflicting keys: %s", e.Composite, strings.Join(keys, "; "))
+}
+
+// Composite merges multiple sources as a single logical source.
+type Composite struct {
+	source.Base
+	sources []source.Source
+	policy  ConflictPolicy
+
+	mu        sync.Mutex
+	conflicts []Conflict
+}
+
+func NewComposite(name string, priority int, sources ...source.Source) *Composite {
+	return &Composite{
+		Base:    source.NewBase(name, priority),
+		sources: sources,
+	}
+}
+
+// WithConflictPolicy sets how conflicting keys are resolved.
+func (s *Composite) WithConflictPolicy(policy ConflictPolicy) *Composite {
+	s.policy = policy
+	return s
+}
+
+// Conflicts returns the conflicts resolved by the last load. They are
+// recorded under every policy except LastWins.
+func (s *Composite) Conflicts() []Conflict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.conflicts)
+}
+
+func (s *Composite) Load() (map[string]any, error) {
+	merged := make(map[string]any)
+	owners := make(map[string]int) // key -> index into s.sources of the kept value
+	seen := make(map[string]*Conflict)
+	var conflicts []*Conflict
+
+	for i, src := range s.sources {
+		data, err := src.Load()
+		if err != nil {
+			return nil, fmt.Errorf("composite source %s: %w", src.Name(), err)
+		}
+		if s.policy == LastWins {
+			deepMerge(merged, data)
+			continue
+		}
+
+		for _, k := range sortedKeys(data) {
+			v := data[k]
+			prev, exists := merged[k]
+			if !exists {
+				merged[k] = v
+				owners[k] = i
+				continue
+			}
+			if isMap(prev) && isMap(v) {
+				deepMerge(merged, map[string]any{k: v})
+				continue
+			}
+			if fmt.Sprint(prev) == fmt.Sprint(v) {
+				continue
+			}
+
+			c, ok := seen[k]
+			if !ok {
+				owner := s.sources[owners[k]].Name()
+				c = &Conflict{Key: k, Sources: []string{owner}, Values: []any{prev}, Winner: owner}
+				seen[k] = c
+				conflicts = append(conflicts, c)
+			}
+			c.Sources = append(c.Sources, src.Name())
+			c.Values = append(c.Values, v)
+			if s.policy != FirstWins {
+				merged[k] = v
+				owners[k] = i
+				c.Winner = src.Name()
+			}
+		}
+	}
+
+	resolved := make([]Conflict, len(conflicts))
+	for i, c := range conflicts {
+		resolved[i] = *c
+	}
+	s.mu.Lock()
+	s.conflicts = resolved
+	s.mu.Unlock()
+
+	if s.policy == ErrorOnConflict && len(resolved) > 0 {
+		return nil, &ConflictError{Composite: s.Name(), Conflicts: resolved}
+	}
+	return merged, nil
+}
+
+// Sources returns the composite's child sources.
+func (s *Composite) Sources() []source.Source { return s.sources }
+
+func (s *Composite) WatchPaths() []string {
+	var paths []string
+	for _, src := range s.sources {
+		paths = append(paths, src.WatchPaths()...)
+	}
+	return paths
+}
+
+// AddSource adds a source to the composite.
+func (s *Composite) AddSource(src source.Source) {
+	s.sources = append(s.sources, src)
+}
+
+// =============================================================================
+// Conditional Source
+// =============================================================================
+
+// Conditional loads data conditionally based on a predicate.
+type Conditional struct {
+	source.Base
+	source    source.Source
+	condition func() bool
+}
+
+func NewConditional(src source.Source, condition func() bool) *Conditional {
+	return &Conditional{
+		Base:      source.NewBase("conditional:"+src.Name(), src.Priority()),
+		source:    src,
+		condition: condition,
+	}
+}
+
+func (s *Conditional) Load() (map[string]any, error) {
+	if !s.condition() {
+		return make(map[string]any), nil
+	}
+	return s.source.Load()
+}
+
+// Unwrap returns the wrapped source.
+func (s *Conditional) Unwrap() source.Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *Conditional) Priority() int { return s.source.Priority() }
+
+func (s *Conditional) WatchPaths() []string {
+	if s.condition() {
+		return s.source.WatchPaths()
+	}
+	return nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func isMap(v any) bool {
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func deepMerge(dst, src map[string]any) {
+	for k, v := range src {
+		if dstMap, ok := dst[k].(map[string]any); ok {
+			if srcMap, ok := v.(map[string]any); ok {
+				deepMerge(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
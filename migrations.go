@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+)
+
+// =============================================================================
+// Schema Migrations
+// =============================================================================
+
+// VersionKey holds a source's schema version. Sources without it are not
+// migrated.
+const VersionKey = "config_version"
+
+// AppliedMigration records a migration applied to a source during a load.
+type AppliedMigration struct {
+	Source string
+	From   int
+	To     int
+}
+
+type migration struct {
+	to int
+	fn func(data map[string]any) error
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = make(map[int]migration)
+)
+
+// RegisterMigration registers fn to upgrade data at schema version from
+// to version to. At load, each source declaring a VersionKey is upgraded
+// in memory through every registered step, and its VersionKey is updated;
+// the files themselves are untouched. fn receives the keys as the source
+// returns them, usually flat dotted keys, and may modify the map in
+// place. Registering the same from version again replaces the previous
+// migration. It panics unless to > from.
+func RegisterMigration(from, to int, fn func(data map[string]any) error) {
+	if to <= from {
+		panic(fmt.Sprintf("config: migration from %d to %d does not move forward", from, to))
+	}
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	migrations[from] = migration{to: to, fn: fn}
+}
+
+// migrate upgrades a source's data, returning a migrated copy and the
+// steps applied. Data without a VersionKey is returned unchanged.
+func migrate(source string, data map[string]any) (map[string]any, []AppliedMigration, error) {
+	raw, ok := data[VersionKey]
+	if !ok {
+		return data, nil, nil
+	}
+	version, ok := asInt(raw)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: not an integer: %v", VersionKey, raw)
+	}
+
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	var applied []AppliedMigration
+	for {
+		m, ok := migrations[version]
+		if !ok {
+			return data, applied, nil
+		}
+		if applied == nil {
+			data = deepCloneMap(data) // sources may hand out shared maps
+		}
+		if err := m.fn(data); err != nil {
+			return nil, nil, fmt.Errorf("migrate %s from %d to %d: %w", VersionKey, version, m.to, err)
+		}
+		applied = append(applied, AppliedMigration{Source: source, From: version, To: m.to})
+		version = m.to
+		data[VersionKey] = version
+	}
+}
+
+// RenameKey moves key, and any keys nested under it, to newKey, for use in
+// migrations. It reports whether anything was moved.
+func RenameKey(data map[string]any, key, newKey string) bool {
+	moved := make(map[string]any)
+	for k, v := range data {
+		if k == key {
+			moved[newKey] = v
+		} else if rest, under := cutKeyPrefix(k, key); under {
+			moved[joinKeys(newKey, rest)] = v
+		} else {
+			continue
+		}
+		delete(data, k)
+	}
+	for k, v := range moved {
+		data[k] = v
+	}
+	return len(moved) > 0
+}
+
+// describeMigrations renders applied migrations as "source: 1->2->3".
+func describeMigrations(applied []AppliedMigration) []string {
+	var out []string
+	for i, m := range applied {
+		if i > 0 && applied[i-1].Source == m.Source {
+			out[len(out)-1] += fmt.Sprintf("->%d", m.To)
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: %d->%d", m.Source, m.From, m.To))
+	}
+	return out
+}
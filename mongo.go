@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// MongoDB Source
+// =============================================================================
+
+// MongoCollection is the slice of a MongoDB collection the source needs.
+// Adapt the official driver's *mongo.Collection to it; documents may be
+// bson.M / bson.D values, which are normalized before flattening.
+type MongoCollection interface {
+	Find(ctx context.Context, filter map[string]any) ([]map[string]any, error)
+	// Watch opens a change stream on the collection. Return nil, nil when
+	// change streams are unavailable (e.g. a standalone server); the source
+	// then falls back to polling.
+	Watch(ctx context.Context) (MongoChangeStream, error)
+}
+
+// MongoChangeStream is satisfied by the driver's *mongo.ChangeStream.
+type MongoChangeStream interface {
+	TryNext(ctx context.Context) bool
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// MongoOptions configure a MongoDB source.
+type MongoOptions struct {
+	// Filter selects the configuration documents, e.g.
+	// {"app": "checkout", "env": "prod"}. Filter fields are not loaded as keys.
+	Filter map[string]any
+	// KeyField nests each document under the value of this field, for
+	// collections holding one document per section. When empty, documents
+	// are merged at the root in the order Find returns them.
+	KeyField string
+	Prefix   string // key namespace for the documents
+
+	RefreshInterval time.Duration // poll interval without change streams, defaults to 1m
+}
+
+// MongoSource loads configuration documents from a MongoDB collection.
+// When a change stream is available, Poll reloads as soon as the collection
+// changes; otherwise it refetches every RefreshInterval.
+type MongoSource struct {
+	BaseSource
+	coll MongoCollection
+	opts MongoOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	stream   MongoChangeStream
+	noStream bool
+	fetched  bool
+	nextPoll time.Time
+}
+
+func Mongo(coll MongoCollection, opts MongoOptions) *MongoSource {
+	return MongoWithPriority(coll, opts, DefaultRemotePriority)
+}
+
+func MongoWithPriority(coll MongoCollection, opts MongoOptions, priority int) *MongoSource {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	return &MongoSource{
+		BaseSource: NewBaseSource(fmt.Sprintf("mongo:%v", opts.Filter), priority),
+		coll:       coll,
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *MongoSource) Remote() bool { return true }
+
+// Load returns the documents, fetching them on first use.
+func (s *MongoSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll drains the change stream, refetching when it delivered any event.
+// Without a change stream it refetches once the refresh interval elapsed.
+func (s *MongoSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if s.stream == nil && !s.noStream {
+		stream, err := s.coll.Watch(ctx)
+		if err != nil {
+			return false, fmt.Errorf("mongo watch: %w", err)
+		}
+		s.stream, s.noStream = stream, stream == nil
+		if stream != nil {
+			// Changes made before the stream opened are picked up below.
+			return s.fetch(ctx)
+		}
+	}
+
+	if s.stream != nil {
+		events := false
+		for s.stream.TryNext(ctx) {
+			events = true
+		}
+		if err := s.stream.Err(); err != nil {
+			s.stream.Close(ctx)
+			s.stream = nil
+			return false, fmt.Errorf("mongo change stream: %w", err)
+		}
+		if !events {
+			return false, nil
+		}
+		return s.fetch(ctx)
+	}
+
+	if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch(ctx)
+}
+
+// Close releases the change stream, if one is open.
+func (s *MongoSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		return nil
+	}
+	err := s.stream.Close(context.Background())
+	s.stream = nil
+	return err
+}
+
+func (s *MongoSource) fetch(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	docs, err := s.coll.Find(ctx, s.opts.Filter)
+	if err != nil {
+		return false, fmt.Errorf("mongo find: %w", err)
+	}
+
+	data := make(map[string]any)
+	for _, doc := range docs {
+		fields, _ := normalizeDocument(doc).(map[string]any)
+		prefix := s.opts.Prefix
+		if s.opts.KeyField != "" {
+			name, ok := fields[s.opts.KeyField]
+			if !ok {
+				continue
+			}
+			prefix = joinKeys(prefix, fmt.Sprint(name))
+			delete(fields, s.opts.KeyField)
+		}
+		delete(fields, "_id")
+		for k := range s.opts.Filter {
+			delete(fields, k)
+		}
+		for k, v := range fields {
+			flatten(joinKeys(prefix, k), v, data)
+		}
+	}
+
+	changed := !s.fetched || !bytes.Equal(canonicalBytes(data), canonicalBytes(s.data))
+	s.data = data
+	s.fetched = true
+	return changed, nil
+}
+
+// normalizeDocument converts driver types (bson.M, bson.A, bson.D) into
+// plain maps and slices. Ordered documents are recognized as slices of
+// structs with Key and Value fields.
+func normalizeDocument(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = normalizeDocument(iter.Value().Interface())
+		}
+		return out
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		if elem := rv.Type().Elem(); elem.Kind() == reflect.Struct {
+			key, hasKey := elem.FieldByName("Key")
+			_, hasValue := elem.FieldByName("Value")
+			if hasKey && hasValue && key.Type.Kind() == reflect.String {
+				out := make(map[string]any, rv.Len())
+				for i := 0; i < rv.Len(); i++ {
+					e := rv.Index(i)
+					out[e.FieldByName("Key").String()] = normalizeDocument(e.FieldByName("Value").Interface())
+				}
+				return out
+			}
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = normalizeDocument(rv.Index(i).Interface())
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Numeric Precision
+// =============================================================================
+
+// Decoded numbers become int when they are integers that fit, float64 when
+// they have a fraction or exponent, and json.Number when they are integers
+// too large for int64, so IDs and large counters survive intact instead of
+// being rounded through float64. The Get* accessors, binding, rules and
+// queries all accept json.Number.
+
+// decodeJSON unmarshals like json.Unmarshal, normalizing numbers as above.
+func decodeJSON(raw []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+
+	switch x := v.(type) {
+	case *map[string]any:
+		for k, val := range *x {
+			(*x)[k] = normalizeNumbers(val)
+		}
+	case *any:
+		*x = normalizeNumbers(*x)
+	}
+	return nil
+}
+
+// normalizeNumbers replaces json.Number values throughout v.
+func normalizeNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		return numberValue(x)
+	case map[string]any:
+		for k, val := range x {
+			x[k] = normalizeNumbers(val)
+		}
+	case []any:
+		for i, val := range x {
+			x[i] = normalizeNumbers(val)
+		}
+	}
+	return v
+}
+
+// numberValue converts n to int or float64, keeping it as json.Number only
+// when it is an integer outside the int64 range.
+func numberValue(n json.Number) any {
+	if i, err := strconv.ParseInt(string(n), 10, 0); err == nil {
+		return int(i)
+	}
+	if isInteger(string(n)) {
+		return n
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n
+}
+
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeYAML unmarshals like yaml.Unmarshal, except that integers beyond
+// the uint64 range, which yaml.v3 rounds to float64, become json.Number.
+// Integers within range already decode to int or uint64.
+func decodeYAML(raw []byte, v any) error {
+	m, ok := v.(*map[string]any)
+	if !ok {
+		return yaml.Unmarshal(raw, v)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	if err := node.Decode(m); err != nil {
+		return err
+	}
+	if *m != nil {
+		restoreBigInts(&node, *m)
+	}
+	return nil
+}
+
+// restoreBigInts walks node alongside its decoded value v and returns v
+// with oversized integer scalars replaced.
+func restoreBigInts(node *yaml.Node, v any) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return restoreBigInts(node.Content[0], v)
+		}
+	case yaml.AliasNode:
+		return restoreBigInts(node.Alias, v)
+	case yaml.MappingNode:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if val, exists := m[key]; exists {
+				m[key] = restoreBigInts(node.Content[i+1], val)
+			}
+		}
+	case yaml.SequenceNode:
+		list, ok := v.([]any)
+		if !ok || len(list) != len(node.Content) {
+			return v
+		}
+		for i, item := range node.Content {
+			list[i] = restoreBigInts(item, list[i])
+		}
+	case yaml.ScalarNode:
+		// Untagged integer literals that overflow are resolved as !!float.
+		if _, isFloat := v.(float64); isFloat && (node.Style == 0 || node.ShortTag() == "!!int") {
+			digits := strings.TrimPrefix(strings.ReplaceAll(node.Value, "_", ""), "+")
+			if isInteger(digits) {
+				return json.Number(digits)
+			}
+		}
+	}
+	return v
+}
+
+// asNumber returns a json.Number as int or float64 and leaves other values
+// unchanged; validation uses it so numeric rules see a number, not a string.
+func asNumber(v any) any {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
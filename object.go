@@ -0,0 +1,266 @@
+package config
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Object Storage Source
+// =============================================================================
+
+// ObjectOptions configure an object storage source.
+type ObjectOptions struct {
+	// Version pins an object version (S3 versionId, GCS generation, Azure
+	// versionid). Pinned objects never change, so Poll always reports false.
+	Version string
+	// CustomerKey is a 256-bit key for customer-provided server-side
+	// encryption (S3 SSE-C, GCS CSEK, Azure CPK). Objects encrypted with
+	// provider-managed keys (SSE-S3, SSE-KMS, ...) need no extra options.
+	CustomerKey []byte
+
+	Region   string // S3 region, defaults to AWS_REGION / AWS_DEFAULT_REGION
+	Endpoint string // S3-compatible endpoint (path-style), e.g. MinIO
+
+	RefreshInterval time.Duration // minimum time between polls, defaults to 1m
+
+	AWSCredentials   AWSCredentialsProvider // defaults to DefaultAWSCredentials
+	GCPCredentials   GCPTokenProvider       // defaults to ApplicationDefaultCredentials
+	AzureCredentials AzureTokenProvider     // defaults to ManagedIdentity("")
+	Auth             AuthProvider           // replaces the default credentials when set
+	Client           *http.Client
+}
+
+// ObjectSource loads a configuration file stored in a bucket, addressed as
+// s3://bucket/key, gs://bucket/object or azblob://account/container/blob.
+// The object is decoded by its extension (falling back to Content-Type) and
+// polled with conditional requests, so unchanged objects cost a 304.
+type ObjectSource struct {
+	BaseSource
+	rawURL string
+	opts   ObjectOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	etag     string
+	version  string
+	fetched  bool
+	nextPoll time.Time
+}
+
+func Object(rawURL string, opts ObjectOptions) *ObjectSource {
+	return ObjectWithPriority(rawURL, opts, DefaultRemotePriority)
+}
+
+func ObjectWithPriority(rawURL string, opts ObjectOptions, priority int) *ObjectSource {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	if opts.AWSCredentials == nil && opts.Auth == nil {
+		opts.AWSCredentials = DefaultAWSCredentials()
+	}
+	if opts.GCPCredentials == nil && opts.Auth == nil {
+		opts.GCPCredentials = ApplicationDefaultCredentials()
+	}
+	if opts.AzureCredentials == nil && opts.Auth == nil {
+		opts.AzureCredentials = ManagedIdentity("")
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+	return &ObjectSource{
+		BaseSource: NewBaseSource("object:"+rawURL, priority),
+		rawURL:     rawURL,
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *ObjectSource) Remote() bool { return true }
+
+// Load returns the decoded object, fetching it on first use.
+func (s *ObjectSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether the object's ETag changed since the last fetch.
+func (s *ObjectSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetched && (s.opts.Version != "" || time.Now().Before(s.nextPoll)) {
+		return false, nil
+	}
+	return s.fetch(context.Background())
+}
+
+// Version returns the version ID of the last fetched object, when the
+// bucket is versioned.
+func (s *ObjectSource) Version() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+func (s *ObjectSource) fetch(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	u, err := url.Parse(s.rawURL)
+	if err != nil {
+		return false, fmt.Errorf("object %s: %w", s.rawURL, err)
+	}
+
+	header := http.Header{}
+	if s.etag != "" {
+		header.Set("If-None-Match", s.etag)
+	}
+
+	var resp *http.Response
+	var body []byte
+	var versionHeader string
+	switch u.Scheme {
+	case "s3":
+		resp, body, err = s.getS3(ctx, u, header)
+		versionHeader = "X-Amz-Version-Id"
+	case "gs":
+		resp, body, err = s.getGCS(ctx, u, header)
+		versionHeader = "X-Goog-Generation"
+	case "azblob":
+		resp, body, err = s.getAzure(ctx, u, header)
+		versionHeader = "X-Ms-Version-Id"
+	default:
+		return false, fmt.Errorf("object %s: unsupported scheme %q", s.rawURL, u.Scheme)
+	}
+	if err != nil {
+		return false, fmt.Errorf("object %s: %w", s.rawURL, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		s.fetched = true
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("object %s: %s: %s", s.rawURL, resp.Status, body)
+	}
+
+	decoder := decoderFor(u.Path)
+	if filepath.Ext(u.Path) == "" {
+		decoder = contentDecoder(resp.Header.Get("Content-Type"))
+	}
+	var decoded map[string]any
+	if err := decoder.Decode(body, &decoded); err != nil {
+		return false, fmt.Errorf("object %s: decode: %w", s.rawURL, err)
+	}
+
+	changed := !s.fetched || resp.Header.Get("ETag") != s.etag
+	s.data = flattenToDot(decoded)
+	s.etag = resp.Header.Get("ETag")
+	s.version = resp.Header.Get(versionHeader)
+	s.fetched = true
+	return changed, nil
+}
+
+func (s *ObjectSource) getS3(ctx context.Context, u *url.URL, header http.Header) (*http.Response, []byte, error) {
+	region := awsRegion(s.opts.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := &url.URL{Scheme: "https", Host: u.Host + ".s3." + region + ".amazonaws.com", Path: u.Path}
+	if s.opts.Endpoint != "" {
+		base, err := url.Parse(s.opts.Endpoint)
+		if err != nil {
+			return nil, nil, err
+		}
+		endpoint = &url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/" + u.Host + u.Path}
+	}
+	if s.opts.Version != "" {
+		endpoint.RawQuery = url.Values{"versionId": {s.opts.Version}}.Encode()
+	}
+
+	if key := s.opts.CustomerKey; key != nil {
+		sum := md5.Sum(key)
+		header.Set("X-Amz-Server-Side-Encryption-Customer-Algorithm", "AES256")
+		header.Set("X-Amz-Server-Side-Encryption-Customer-Key", base64.StdEncoding.EncodeToString(key))
+		header.Set("X-Amz-Server-Side-Encryption-Customer-Key-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return doAWSRequest(ctx, s.opts.Client, s.opts.AWSCredentials, http.MethodGet, endpoint.String(), region, "s3", nil, header)
+}
+
+func (s *ObjectSource) getGCS(ctx context.Context, u *url.URL, header http.Header) (*http.Response, []byte, error) {
+	q := url.Values{"alt": {"media"}}
+	if s.opts.Version != "" {
+		q.Set("generation", s.opts.Version)
+	}
+	endpoint := "https://storage.googleapis.com/storage/v1/b/" + url.PathEscape(u.Host) +
+		"/o/" + url.PathEscape(strings.TrimPrefix(u.Path, "/")) + "?" + q.Encode()
+
+	if creds := s.opts.GCPCredentials; creds != nil {
+		token, err := creds.Token(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		header.Set("Authorization", "Bearer "+token)
+	}
+	if key := s.opts.CustomerKey; key != nil {
+		sum := sha256.Sum256(key)
+		header.Set("X-Goog-Encryption-Algorithm", "AES256")
+		header.Set("X-Goog-Encryption-Key", base64.StdEncoding.EncodeToString(key))
+		header.Set("X-Goog-Encryption-Key-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return s.get(ctx, endpoint, header)
+}
+
+func (s *ObjectSource) getAzure(ctx context.Context, u *url.URL, header http.Header) (*http.Response, []byte, error) {
+	endpoint := &url.URL{Scheme: "https", Host: u.Host + ".blob.core.windows.net", Path: u.Path}
+	if s.opts.Version != "" {
+		endpoint.RawQuery = url.Values{"versionid": {s.opts.Version}}.Encode()
+	}
+
+	header.Set("X-Ms-Version", "2021-08-06")
+	if key := s.opts.CustomerKey; key != nil {
+		sum := sha256.Sum256(key)
+		header.Set("X-Ms-Encryption-Algorithm", "AES256")
+		header.Set("X-Ms-Encryption-Key", base64.StdEncoding.EncodeToString(key))
+		header.Set("X-Ms-Encryption-Key-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return azureRequest(ctx, s.opts.Client, s.opts.AzureCredentials, "https://storage.azure.com", endpoint.String(), header)
+}
+
+func (s *ObjectSource) get(ctx context.Context, endpoint string, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header = header
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
@@ -0,0 +1,548 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Change Sets
+// =============================================================================
+
+// ChangeSet is a batch of configuration changes delivered to observers.
+type ChangeSet struct {
+	Changed  map[string]any // new values of changed keys
+	Previous map[string]any // prior values; absent for newly added keys
+	At       time.Time      // when the most recent change was applied
+
+	aborted  bool
+	trailing bool // marks a request to deliver an observer's held changes
+}
+
+// Abort marks the change set as handled; observers with a higher priority
+// value are not notified.
+func (cs *ChangeSet) Abort() { cs.aborted = true }
+
+// Aborted reports whether an earlier observer aborted delivery.
+func (cs *ChangeSet) Aborted() bool { return cs.aborted }
+
+// merge folds a later change set into cs. The earliest previous value of each
+// key is kept so the merged set still describes the transition as a whole.
+func (cs *ChangeSet) merge(next *ChangeSet) {
+	for k, v := range next.Changed {
+		if _, seen := cs.Changed[k]; !seen {
+			if old, ok := next.Previous[k]; ok {
+				cs.Previous[k] = old
+			}
+		}
+		cs.Changed[k] = v
+	}
+	cs.At = next.At
+}
+
+// =============================================================================
+// Observer Registration & Ordered Delivery
+// =============================================================================
+
+// DefaultObserverPriority is used for observers registered without a priority.
+const DefaultObserverPriority = 100
+
+// ChangeSetObserver is an Observer variant that receives the full change set,
+// including previous values, and may abort delivery to later observers.
+// The change set is shared between observers and must not be modified.
+type ChangeSetObserver interface {
+	OnChangeSet(cs *ChangeSet)
+}
+
+// ChangeSetObserverFunc adapts a function to the ChangeSetObserver interface.
+type ChangeSetObserverFunc func(cs *ChangeSet)
+
+func (f ChangeSetObserverFunc) OnChangeSet(cs *ChangeSet) { f(cs) }
+
+// ObserveChanges registers an observer receiving full change sets.
+func (c *Config) ObserveChanges(obs ChangeSetObserver, opts ...ObserverOption) *Config {
+	return c.addObserver(&observerEntry{changes: obs}, opts)
+}
+
+// ObserverOption configures an observer registration.
+type ObserverOption func(*observerEntry)
+
+// WithObserverPriority sets the delivery priority; lower values are notified
+// first (e.g. connection pools before application-level observers).
+func WithObserverPriority(priority int) ObserverOption {
+	return func(e *observerEntry) {
+		e.priority = priority
+	}
+}
+
+// observerEntry holds a registered observer; exactly one of observer and
+// changes is set.
+type observerEntry struct {
+	observer   Observer
+	changes    ChangeSetObserver
+	priority   int
+	limit      *rateLimiter
+	deadLetter func(cs *ChangeSet)
+
+	// rebind rebuilds observer for another config; set by observers
+	// that read the config they were registered on, such as ObserveBound.
+	rebind func(c *Config) Observer
+
+	config *Config
+	held   heldChanges
+}
+
+// heldChanges merges change sets held back by an observer's rate limit
+// until the limit allows delivering them.
+type heldChanges struct {
+	mu    sync.Mutex
+	set   *ChangeSet
+	timer *time.Timer
+}
+
+// cloneFor copies the entry for a config cloned from the one it is
+// registered on. Config-bound observers are rebuilt for c, and the rate
+// limit starts afresh rather than sharing state with the original.
+func (e *observerEntry) cloneFor(c *Config) *observerEntry {
+	cp := observerEntry{
+		observer:   e.observer,
+		changes:    e.changes,
+		priority:   e.priority,
+		deadLetter: e.deadLetter,
+		rebind:     e.rebind,
+		config:     c,
+	}
+	if e.rebind != nil {
+		cp.observer = e.rebind(c)
+	}
+	if e.limit != nil {
+		cp.limit = newRateLimiter(e.limit.interval, int(e.limit.burst))
+	}
+	return &cp
+}
+
+func (c *Config) addObserver(entry *observerEntry, opts []ObserverOption) *Config {
+	entry.priority = DefaultObserverPriority
+	for _, opt := range opts {
+		opt(entry)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.config = c
+	c.observers = append(c.observers, entry)
+	sortObservers(c.observers)
+	return c
+}
+
+// notify delivers a change set to the observer. Over the rate limit, the
+// set goes to the dead letter handler and is held, merged with later sets,
+// until the limit allows delivering them. notify runs on the delivery
+// goroutine only.
+func (e *observerEntry) notify(cs *ChangeSet) {
+	if e.limit != nil {
+		if cs.trailing {
+			if cs = e.takeHeld(); cs == nil {
+				return
+			}
+			if !e.limit.allow(time.Now()) {
+				e.hold(cs)
+				return
+			}
+		} else if e.holding() || !e.limit.allow(time.Now()) {
+			// Later sets queue behind held ones so values never go back.
+			if e.deadLetter != nil {
+				e.deadLetter(cs)
+			}
+			e.hold(cs)
+			return
+		}
+	}
+	if e.changes != nil {
+		e.changes.OnChangeSet(cs)
+		return
+	}
+	e.observer.OnConfigChange(cloneMap(cs.Changed))
+}
+
+// hold merges cs into the held changes and schedules their delivery for
+// when the rate limit next allows one.
+func (e *observerEntry) hold(cs *ChangeSet) {
+	e.held.mu.Lock()
+	defer e.held.mu.Unlock()
+	if e.held.set == nil {
+		e.held.set = &ChangeSet{Changed: make(map[string]any), Previous: make(map[string]any)}
+	}
+	e.held.set.merge(cs)
+	if e.held.timer == nil {
+		e.held.timer = time.AfterFunc(e.limit.wait(time.Now()), e.flushHeld)
+	}
+}
+
+func (e *observerEntry) holding() bool {
+	e.held.mu.Lock()
+	defer e.held.mu.Unlock()
+	return e.held.set != nil
+}
+
+func (e *observerEntry) takeHeld() *ChangeSet {
+	e.held.mu.Lock()
+	defer e.held.mu.Unlock()
+	cs := e.held.set
+	e.held.set, e.held.timer = nil, nil
+	return cs
+}
+
+// flushHeld queues delivery of the held changes behind any change sets
+// already queued, keeping them in order.
+func (e *observerEntry) flushHeld() {
+	if e.config == nil || e.config.ctx.Err() != nil {
+		return
+	}
+	e.config.delivery.enqueue(&ChangeSet{trailing: true}, []*observerEntry{e})
+}
+
+// sortObservers orders observers by priority, keeping registration order
+// for equal priorities.
+func sortObservers(entries []*observerEntry) {
+	for i := 1; i < len(entries); i++ {
+		cur := entries[i]
+		j := i - 1
+		for j >= 0 && entries[j].priority > cur.priority {
+			entries[j+1] = entries[j]
+			j--
+		}
+		entries[j+1] = cur
+	}
+}
+
+// deliveryQueue delivers change sets one at a time on a single worker
+// goroutine, preserving both change order and observer priority order.
+type deliveryQueue struct {
+	mu      sync.Mutex
+	queue   []delivery
+	running bool
+	idle    *sync.Cond // signalled when the worker stops; created by wait
+}
+
+type delivery struct {
+	changes   *ChangeSet
+	observers []*observerEntry
+}
+
+func (q *deliveryQueue) enqueue(cs *ChangeSet, observers []*observerEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queue = append(q.queue, delivery{changes: cs, observers: observers})
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *deliveryQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.queue) == 0 {
+			q.running = false
+			if q.idle != nil {
+				q.idle.Broadcast()
+			}
+			q.mu.Unlock()
+			return
+		}
+		next := q.queue[0]
+		q.queue = q.queue[1:]
+		q.mu.Unlock()
+
+		for _, obs := range next.observers {
+			if next.changes.Aborted() {
+				break
+			}
+			obs.notify(next.changes)
+		}
+	}
+}
+
+// wait blocks until every queued change set has been delivered.
+func (q *deliveryQueue) wait() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.running {
+		if q.idle == nil {
+			q.idle = sync.NewCond(&q.mu)
+		}
+		q.idle.Wait()
+	}
+}
+
+// =============================================================================
+// Observer Rate Limiting
+// =============================================================================
+
+// WithObserverRateLimit notifies the observer of at most burst change sets
+// at once and one per interval on average after that, so a backend
+// flapping values cannot overwhelm expensive observers (e.g. ones that
+// restart worker pools). Change sets over the limit are held and merged,
+// and the merged set is delivered as soon as the limit allows, so the
+// observer always catches up with the latest values. Each held set is
+// also passed to the dead letter handler, if any.
+func WithObserverRateLimit(interval time.Duration, burst int) ObserverOption {
+	return func(e *observerEntry) {
+		e.limit = newRateLimiter(interval, burst)
+	}
+}
+
+// WithDeadLetter sets the handler recording change sets held back by the
+// observer's rate limit, e.g. a DeadLetterBuffer's Capture. The observer
+// still receives them later, merged. It runs on the delivery goroutine and
+// must not block.
+func WithDeadLetter(fn func(cs *ChangeSet)) ObserverOption {
+	return func(e *observerEntry) {
+		e.deadLetter = fn
+	}
+}
+
+// rateLimiter is a token bucket refilled one token per interval.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newRateLimiter(interval time.Duration, burst int) *rateLimiter {
+	burst = max(burst, 1)
+	return &rateLimiter{interval: interval, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (l *rateLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.interval <= 0 {
+		return true
+	}
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// wait returns how long until allow next succeeds.
+func (l *rateLimiter) wait(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tokens := l.tokens
+	if !l.last.IsZero() {
+		tokens = min(l.burst, tokens+float64(now.Sub(l.last))/float64(l.interval))
+	}
+	if l.interval <= 0 || tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) * float64(l.interval))
+}
+
+// DeadLetterBuffer keeps the most recent change sets held back by observer
+// rate limits, for inspection or auditing.
+type DeadLetterBuffer struct {
+	mu      sync.Mutex
+	size    int
+	sets    []*ChangeSet
+	dropped int
+}
+
+// NewDeadLetterBuffer returns a buffer holding up to size change sets;
+// older ones are discarded first.
+func NewDeadLetterBuffer(size int) *DeadLetterBuffer {
+	return &DeadLetterBuffer{size: max(size, 1)}
+}
+
+// Capture records a held-back change set. Pass it to WithDeadLetter.
+func (b *DeadLetterBuffer) Capture(cs *ChangeSet) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.dropped++
+	if len(b.sets) == b.size {
+		b.sets = b.sets[1:]
+	}
+	b.sets = append(b.sets, cs)
+}
+
+// Drain returns the buffered change sets, oldest first, and empties the
+// buffer.
+func (b *DeadLetterBuffer) Drain() []*ChangeSet {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := b.sets
+	b.sets = nil
+	return out
+}
+
+// Merged drains the buffer into one change set describing the whole
+// transition. It returns nil when the buffer is empty.
+func (b *DeadLetterBuffer) Merged() *ChangeSet {
+	var out *ChangeSet
+	for _, cs := range b.Drain() {
+		if out == nil {
+			out = &ChangeSet{Changed: map[string]any{}, Previous: map[string]any{}}
+		}
+		out.merge(cs)
+	}
+	return out
+}
+
+// Dropped returns how many change sets were captured in total, including
+// discarded ones.
+func (b *DeadLetterBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// =============================================================================
+// Change Coalescing
+// =============================================================================
+
+// coalescer buffers change sets for a window before delivering them as one.
+type coalescer struct {
+	mu      sync.Mutex
+	window  time.Duration
+	pending *ChangeSet
+	timer   *time.Timer
+}
+
+// stop cancels the pending delivery, if any.
+func (co *coalescer) stop() {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+	if co.timer != nil {
+		co.timer.Stop()
+	}
+	co.pending, co.timer = nil, nil
+}
+
+// publishChanges delivers a change set immediately, or buffers it when a
+// coalescing window is configured. The caller must hold c.mu.
+func (c *Config) publishChanges(cs *ChangeSet) {
+	if c.coalesce.window <= 0 {
+		c.notifyObservers(cs)
+		return
+	}
+
+	c.coalesce.mu.Lock()
+	defer c.coalesce.mu.Unlock()
+
+	// Each window collects into its own change set: cs itself may already
+	// be held by the event bus or history and must not change.
+	if c.coalesce.pending == nil {
+		c.coalesce.pending = &ChangeSet{Changed: make(map[string]any), Previous: make(map[string]any)}
+		c.coalesce.timer = time.AfterFunc(c.coalesce.window, c.flushChanges)
+	}
+	c.coalesce.pending.merge(cs)
+}
+
+// flushChanges delivers the buffered change set at the end of a window,
+// unless the config has been closed.
+func (c *Config) flushChanges() {
+	c.coalesce.mu.Lock()
+	cs := c.coalesce.pending
+	c.coalesce.pending, c.coalesce.timer = nil, nil
+	c.coalesce.mu.Unlock()
+
+	if cs == nil || c.ctx.Err() != nil {
+		return
+	}
+	c.mu.RLock()
+	c.notifyObservers(cs)
+	c.mu.RUnlock()
+}
+
+// =============================================================================
+// Bound Observers
+// =============================================================================
+
+// ObserveBound registers an observer that, whenever a change touches keys
+// mapped by T, binds the subtree under prefix into a fresh T, validates it,
+// and delivers it to fn only if both steps succeed. Failures (including
+// errors returned by fn) are reported to the config's error handler.
+func ObserveBound[T any](c *Config, prefix string, fn func(next T) error) *Config {
+	bind := func(c *Config) Observer { return boundObserver(c, prefix, fn) }
+	return c.addObserver(&observerEntry{observer: bind(c), rebind: bind}, nil)
+}
+
+// boundObserver returns the observer behind ObserveBound for c.
+func boundObserver[T any](c *Config, prefix string, fn func(next T) error) Observer {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	return ObserverFunc(func(changed map[string]any) {
+		if !affectsType(typ, prefix, changed) {
+			return
+		}
+
+		c.mu.RLock()
+		data := bindSubtree(c.data, prefix)
+		c.mu.RUnlock()
+
+		next := new(T)
+		if err := c.bindMapToStruct(data, next); err != nil {
+			c.handleError(fmt.Errorf("observe %s: %w", typ, err))
+			return
+		}
+		if err := c.validate.Struct(next); err != nil {
+			c.handleError(fmt.Errorf("observe %s: %w", typ, c.wrapValidationError(err, prefix)))
+			return
+		}
+		if err := fn(*next); err != nil {
+			c.handleError(fmt.Errorf("observe %s: %w", typ, err))
+		}
+	})
+}
+
+// affectsType reports whether any changed key maps onto a field of typ.
+func affectsType(typ reflect.Type, prefix string, changed map[string]any) bool {
+	for key := range changed {
+		rest := key
+		if prefix != "" {
+			var ok bool
+			if rest, ok = cutKeyPrefix(key, prefix); !ok {
+				continue
+			}
+		}
+		if typeHasPath(typ, splitPath(rest)) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeHasPath reports whether a dotted path resolves to a field of typ.
+// Paths continuing into non-struct fields (maps, slices) count as a match.
+func typeHasPath(typ reflect.Type, path []string) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if len(path) == 0 || typ.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.IsExported() && matchField(sf, path[0]) {
+			return typeHasPath(sf.Type, path[1:])
+		}
+	}
+	return false
+}
@@ -0,0 +1,374 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// OCI Artifact Source
+// =============================================================================
+
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+var ociManifestTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.artifact.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// OCIOptions configure an OCI artifact source.
+type OCIOptions struct {
+	// File selects the layer by its title annotation (as set by
+	// `oras push registry/repo:tag config.yaml`). When empty the first layer
+	// with a JSON or YAML media type or title is used.
+	File string
+
+	Username string // registry credentials; anonymous when empty
+	Password string
+	// PlainHTTP talks to the registry over http, for local registries.
+	PlainHTTP bool
+
+	// Verify is called with the manifest digest and body before any layer
+	// is used, e.g. to check a cosign or notation signature.
+	Verify func(digest string, manifest []byte) error
+
+	RefreshInterval time.Duration // minimum time between polls, defaults to 1m
+	Auth            AuthProvider  // authenticates registry requests instead of Username/Password
+	Client          *http.Client
+}
+
+// OCISource pulls a configuration file pushed to an OCI registry as an
+// artifact, referenced by tag ("ghcr.io/acme/app-config:prod") or digest
+// ("...@sha256:..."). Blobs are checked against their digests. Under Watch,
+// a tag is re-resolved with a HEAD request and reloaded when it moves.
+type OCISource struct {
+	BaseSource
+	ref  string
+	opts OCIOptions
+
+	mu       sync.Mutex
+	data     map[string]any
+	digest   string
+	token    string
+	fetched  bool
+	nextPoll time.Time
+}
+
+func OCI(ref string, opts OCIOptions) *OCISource {
+	return OCIWithPriority(ref, opts, DefaultRemotePriority)
+}
+
+func OCIWithPriority(ref string, opts OCIOptions, priority int) *OCISource {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+	return &OCISource{
+		BaseSource: NewBaseSource("oci:"+ref, priority),
+		ref:        ref,
+		opts:       opts,
+		data:       map[string]any{},
+	}
+}
+
+// Remote reports that the source needs the network.
+func (s *OCISource) Remote() bool { return true }
+
+// Load returns the decoded artifact, pulling it on first use.
+func (s *OCISource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.pull(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether the tag now points at a different manifest.
+// Digest references never change.
+func (s *OCISource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if !s.fetched {
+		return s.pull(ctx)
+	}
+	if strings.Contains(s.ref, "@") || time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	ref, err := parseOCIReference(s.ref)
+	if err != nil {
+		return false, err
+	}
+	resp, _, err := s.do(ctx, http.MethodHead, ref, "/manifests/"+ref.reference, ociManifestTypes)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("oci %s: resolve: %s", s.ref, resp.Status)
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" && digest == s.digest {
+		return false, nil
+	}
+	return s.pull(ctx)
+}
+
+// Digest returns the manifest digest of the last pulled artifact.
+func (s *OCISource) Digest() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.digest
+}
+
+type ociReference struct {
+	registry   string
+	repository string
+	reference  string // tag or digest
+}
+
+// parseOCIReference splits "registry/repo:tag" or "registry/repo@digest".
+// References without a registry host resolve to Docker Hub.
+func parseOCIReference(ref string) (ociReference, error) {
+	var out ociReference
+	name := ref
+	if before, digest, ok := strings.Cut(ref, "@"); ok {
+		name, out.reference = before, digest
+	} else if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, out.reference = ref[:i], ref[i+1:]
+	} else {
+		out.reference = "latest"
+	}
+
+	host, repo, ok := strings.Cut(name, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		host, repo = "registry-1.docker.io", name
+		if !strings.Contains(repo, "/") {
+			repo = "library/" + repo
+		}
+	}
+	if repo == "" || out.reference == "" {
+		return ociReference{}, fmt.Errorf("invalid oci reference %q", ref)
+	}
+	out.registry, out.repository = host, repo
+	return out, nil
+}
+
+type ociManifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+	// Artifact manifests list their files as blobs.
+	Blobs []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"blobs"`
+}
+
+func (s *OCISource) pull(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	ref, err := parseOCIReference(s.ref)
+	if err != nil {
+		return false, err
+	}
+
+	resp, manifest, err := s.do(ctx, http.MethodGet, ref, "/manifests/"+ref.reference, ociManifestTypes)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("oci %s: get manifest: %s: %s", s.ref, resp.Status, manifest)
+	}
+	digest := "sha256:" + sha256Hex(manifest)
+	if strings.HasPrefix(ref.reference, "sha256:") && ref.reference != digest {
+		return false, fmt.Errorf("oci %s: manifest digest mismatch: got %s", s.ref, digest)
+	}
+	if s.fetched && digest == s.digest {
+		return false, nil
+	}
+	if s.opts.Verify != nil {
+		if err := s.opts.Verify(digest, manifest); err != nil {
+			return false, fmt.Errorf("oci %s: verify: %w", s.ref, err)
+		}
+	}
+
+	var m ociManifest
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return false, fmt.Errorf("oci %s: decode manifest: %w", s.ref, err)
+	}
+	layers := append(m.Layers, m.Blobs...)
+
+	var layerDigest, title, mediaType string
+	for _, l := range layers {
+		name := l.Annotations[ociTitleAnnotation]
+		if s.opts.File != "" && name != s.opts.File {
+			continue
+		}
+		if s.opts.File == "" && !isConfigFile(name, l.MediaType) {
+			continue
+		}
+		layerDigest, title, mediaType = l.Digest, name, l.MediaType
+		break
+	}
+	if layerDigest == "" {
+		return false, fmt.Errorf("oci %s: no matching config layer", s.ref)
+	}
+
+	resp, blob, err := s.do(ctx, http.MethodGet, ref, "/blobs/"+layerDigest, nil)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("oci %s: get blob: %s", s.ref, resp.Status)
+	}
+	if sum := sha256.Sum256(blob); "sha256:"+hex.EncodeToString(sum[:]) != layerDigest {
+		return false, fmt.Errorf("oci %s: blob digest mismatch", s.ref)
+	}
+
+	decoder := contentDecoder(mediaType)
+	if filepath.Ext(title) != "" {
+		decoder = decoderFor(title)
+	}
+	var decoded map[string]any
+	if err := decoder.Decode(blob, &decoded); err != nil {
+		return false, fmt.Errorf("oci %s: decode: %w", s.ref, err)
+	}
+
+	s.data = flattenToDot(decoded)
+	s.digest = digest
+	s.fetched = true
+	return true, nil
+}
+
+// isConfigFile reports whether a layer looks like a JSON or YAML document.
+func isConfigFile(title, mediaType string) bool {
+	switch strings.ToLower(filepath.Ext(title)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return isJSONContentType(mediaType) || strings.Contains(mediaType, "yaml")
+}
+
+// do sends a registry API request, performing the bearer token handshake
+// when the registry asks for it and no Auth provider is configured.
+func (s *OCISource) do(ctx context.Context, method string, ref ociReference, path string, accept []string) (*http.Response, []byte, error) {
+	scheme := "https"
+	if s.opts.PlainHTTP {
+		scheme = "http"
+	}
+	endpoint := scheme + "://" + ref.registry + "/v2/" + ref.repository + path
+
+	send := func() (*http.Response, []byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if s.token != "" {
+			req.Header.Set("Authorization", "Bearer "+s.token)
+		} else if s.opts.Username != "" {
+			req.SetBasicAuth(s.opts.Username, s.opts.Password)
+		}
+		resp, err := s.opts.Client.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("oci %s: %w", s.ref, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return resp, body, err
+	}
+
+	resp, body, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || s.opts.Auth != nil {
+		return resp, body, err
+	}
+	if err := s.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+		return nil, nil, err
+	}
+	return send()
+}
+
+// authenticate obtains a registry token from the realm named in a
+// WWW-Authenticate challenge.
+func (s *OCISource) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("oci %s: unauthorized", s.ref)
+	}
+
+	fields := map[string]string{}
+	for _, part := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			fields[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+	realm := fields["realm"]
+	if realm == "" {
+		return fmt.Errorf("oci %s: unauthorized: no token realm", s.ref)
+	}
+
+	q := url.Values{}
+	if fields["service"] != "" {
+		q.Set("service", fields["service"])
+	}
+	if fields["scope"] != "" {
+		q.Set("scope", fields["scope"])
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if s.opts.Username != "" {
+		req.SetBasicAuth(s.opts.Username, s.opts.Password)
+	}
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oci %s: token: %w", s.ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oci %s: token: %s", s.ref, resp.Status)
+	}
+
+	var out struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("oci %s: decode token: %w", s.ref, err)
+	}
+	s.token = out.Token
+	if s.token == "" {
+		s.token = out.AccessToken
+	}
+	return nil
+}
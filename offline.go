@@ -0,0 +1,163 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Offline Mode
+// =============================================================================
+
+// RemoteSource is implemented by sources that need the network. Offline
+// mode leaves them unloaded, as well as composites and middleware that
+// contain one.
+type RemoteSource interface {
+	Remote() bool
+}
+
+// Source load statuses used in offline mode.
+const (
+	SourceCached  = "cached"  // loaded from the offline cache
+	SourceOffline = "offline" // skipped, no cached copy
+)
+
+// WithOffline disables remote sources, their polling and webhook
+// notifications. Remote sources are served from the offline cache when
+// one is configured and holds a copy, and contribute nothing otherwise.
+func WithOffline(enabled bool) Option {
+	return func(c *Config) {
+		c.offline = enabled
+	}
+}
+
+// WithOfflineCache keeps a copy of each remote source's last successful
+// load in dir, for use in offline mode. Copies hold the loaded values,
+// secrets included, and are written readable by the owner only.
+func WithOfflineCache(dir string) Option {
+	return func(c *Config) {
+		c.offlineCache = dir
+	}
+}
+
+// Offline reports whether offline mode is enabled.
+func (c *Config) Offline() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offline
+}
+
+// isRemote reports whether src or any source it wraps is remote.
+func isRemote(src Source) bool {
+	remote := false
+	walkSources(src, func(s Source) {
+		if r, ok := s.(RemoteSource); ok && r.Remote() {
+			remote = true
+		}
+	})
+	return remote
+}
+
+// loadSource loads src, honouring offline mode and the offline cache; the
+// caller must hold c.mu. The returned status is SourceOK, SourceCached or
+// SourceOffline; warnings are added to report.
+func (c *Config) loadSource(src Source, report *LoadReport) (map[string]any, string, error) {
+	if !isRemote(src) {
+		data, err := src.Load()
+		return data, SourceOK, err
+	}
+
+	if c.offline {
+		if c.offlineCache != "" {
+			data, saved, err := readOfflineCache(c.offlineCache, src.Name())
+			if err == nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("source %s offline, using copy from %s", src.Name(), saved.Format(time.RFC3339)))
+				return data, SourceCached, nil
+			}
+			if !os.IsNotExist(err) {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("source %s offline, cache unreadable: %v", src.Name(), err))
+			}
+		}
+		report.Warnings = append(report.Warnings, fmt.Sprintf("source %s skipped: offline", src.Name()))
+		return map[string]any{}, SourceOffline, nil
+	}
+
+	data, err := src.Load()
+	if err == nil && c.offlineCache != "" {
+		if err := writeOfflineCache(c.offlineCache, src.Name(), data); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("source %s: offline cache: %v", src.Name(), err))
+		}
+	}
+	return data, SourceOK, err
+}
+
+type offlineCopy struct {
+	Source string         `json:"source"`
+	Saved  time.Time      `json:"saved"`
+	Data   map[string]any `json:"data"`
+}
+
+// offlineCachePath returns a file name that is readable yet unique per
+// source name.
+func offlineCachePath(dir, name string) string {
+	sum := sha256.Sum256([]byte(name))
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+	if len(safe) > 64 {
+		safe = safe[:64]
+	}
+	return filepath.Join(dir, safe+"-"+hex.EncodeToString(sum[:4])+".json")
+}
+
+func writeOfflineCache(dir, name string, data map[string]any) error {
+	raw, err := json.Marshal(offlineCopy{Source: name, Saved: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	path := offlineCachePath(dir, name)
+	tmp, err := os.CreateTemp(dir, ".offline-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readOfflineCache(dir, name string) (map[string]any, time.Time, error) {
+	raw, err := os.ReadFile(offlineCachePath(dir, name))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var cp offlineCopy
+	if err := decodeJSON(raw, &cp); err != nil {
+		return nil, time.Time{}, err
+	}
+	if cp.Source != name {
+		return nil, time.Time{}, fmt.Errorf("copy belongs to source %s", cp.Source)
+	}
+	if cp.Data == nil {
+		cp.Data = map[string]any{}
+	}
+	normalizeNumbers(cp.Data)
+	return cp.Data, cp.Saved, nil
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// =============================================================================
+// Templated Source Paths
+// =============================================================================
+
+// WithPathMeta sets values available in builder source paths as
+// {{.meta.name}}. See AddFile.
+func (b *Builder) WithPathMeta(meta map[string]string) *Builder {
+	if b.pathMeta == nil {
+		b.pathMeta = make(map[string]string, len(meta))
+	}
+	for k, v := range meta {
+		b.pathMeta[k] = v
+	}
+	return b
+}
+
+// pendingPaths stands in for a source whose paths hold placeholders until
+// the builder resolves them, keeping its place in the load order. Loads
+// before then, such as SetActiveProfile's, see no values from it; if the
+// paths could not be resolved, loads fail with the reason. Cloned
+// builders get their own copies, which share the origin of the source they
+// stand in for.
+type pendingPaths struct {
+	BaseSource
+	origin     *pendingPaths
+	paths      []string
+	build      func(paths ...string) Source
+	middleware []SourceMiddleware
+	err        error
+}
+
+// clone copies p for a cloned builder.
+func (p *pendingPaths) clone() *pendingPaths {
+	q := *p
+	q.paths = append([]string(nil), p.paths...)
+	q.middleware = append([]SourceMiddleware(nil), p.middleware...)
+	return &q
+}
+
+func (p *pendingPaths) Load() (map[string]any, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return map[string]any{}, nil
+}
+
+// addPaths adds the source build creates from paths. Paths holding
+// placeholders, variables or ~ are resolved when the config is built, so
+// the active profile and path meta may be set after the source is added.
+func (b *Builder) addPaths(paths []string, build func(paths ...string) Source) *Builder {
+	deferred := false
+	for _, p := range paths {
+		deferred = deferred || strings.Contains(p, "{{") || strings.Contains(p, "$") || hasHomePrefix(p)
+	}
+	if !deferred {
+		return b.AddSource(build(paths...))
+	}
+
+	probe := build(paths...)
+	pending := &pendingPaths{
+		BaseSource: NewBaseSource(probe.Name(), probe.Priority()),
+		paths:      paths,
+		build:      build,
+		middleware: append([]SourceMiddleware(nil), b.middleware...),
+	}
+	pending.origin = pending
+	b.pending = append(b.pending, pending)
+	b.config.AddSource(pending)
+	return b
+}
+
+// resolvePaths replaces pending sources with ones built from their
+// expanded paths. Sources whose paths cannot be resolved stay pending,
+// failing their loads with the error, which is also returned.
+func (b *Builder) resolvePaths() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	meta := b.pathMeta
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	data := map[string]any{"profile": "", "meta": meta}
+	if pm := b.config.profiles; pm != nil {
+		data["profile"] = pm.GetActiveProfile()
+	}
+
+	var unresolved []*pendingPaths
+	var errs []error
+	for _, p := range b.pending {
+		paths, err := resolvePendingPaths(p.paths, data)
+		if err != nil {
+			p.err = err
+			b.config.swapPending(p, p)
+			unresolved = append(unresolved, p)
+			errs = append(errs, err)
+			continue
+		}
+		src := p.build(paths...)
+		if len(p.middleware) > 0 {
+			src = ChainMiddleware(p.middleware...)(src)
+		}
+		b.config.swapPending(p, src)
+	}
+	b.pending = unresolved
+	return errors.Join(errs...)
+}
+
+func resolvePendingPaths(raw []string, data map[string]any) ([]string, error) {
+	paths := make([]string, len(raw))
+	for i, r := range raw {
+		path, err := expandPathTemplate(r, data)
+		if err == nil {
+			path, err = ExpandPath(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+var pathFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"default": func(def, v string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// pathSyntaxError reports a malformed path template.
+type pathSyntaxError struct {
+	path string
+	err  error
+}
+
+func (e *pathSyntaxError) Error() string { return fmt.Sprintf("source path %q: %v", e.path, e.err) }
+func (e *pathSyntaxError) Unwrap() error { return e.err }
+
+// expandPathTemplate executes a path template. Unknown meta keys are
+// errors, so a typo cannot silently select another file.
+func expandPathTemplate(raw string, data map[string]any) (string, error) {
+	if !strings.Contains(raw, "{{") {
+		return raw, nil
+	}
+	tmpl, err := template.New("path").Funcs(pathFuncs).Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return "", &pathSyntaxError{path: raw, err: err}
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("source path %q: %w", raw, err)
+	}
+	return out.String(), nil
+}
+
+// =============================================================================
+// Path Expansion
+// =============================================================================
+
+// ExpandPath expands a leading ~ to the user's home directory and $VAR or
+// ${VAR} to environment variables. ${VAR:-default} falls back to default
+// when VAR is unset or empty, and $$ is a literal $. Other unset variables
+// are errors rather than empty strings, which would silently point the
+// path elsewhere. Builder file paths are expanded this way when built.
+func ExpandPath(path string) (string, error) {
+	raw := path
+	if hasHomePrefix(path) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand path %q: %w", raw, err)
+		}
+		path = home + path[1:]
+	}
+
+	var missing []string
+	out := os.Expand(path, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		name, def, hasDefault := strings.Cut(name, ":-")
+		v, ok := os.LookupEnv(name)
+		switch {
+		case hasDefault && v == "":
+			return def
+		case !ok:
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("expand path %q: variable %s is not set", raw, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// hasHomePrefix reports whether path starts with ~ as a whole segment;
+// ~user forms are left alone.
+func hasHomePrefix(path string) bool {
+	return path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator))
+}
+
+// clonePending copies the builder's pending sources for a clone building
+// cfg. A detached cfg gets the copies in place of the originals.
+func (b *Builder) clonePending(cfg *Config) []*pendingPaths {
+	pending := make([]*pendingPaths, len(b.pending))
+	for i, p := range b.pending {
+		pending[i] = p.clone()
+		if cfg != b.config {
+			cfg.swapPending(pending[i], pending[i])
+		}
+	}
+	return pending
+}
+
+// swapPending replaces the placeholder standing in for the same source as
+// p with src, if one is still present.
+func (c *Config) swapPending(p *pendingPaths, src Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, existing := range c.sources {
+		if e, ok := existing.(*pendingPaths); ok && e.origin == p.origin {
+			c.sources[i] = src
+			c.sortSources()
+			return
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// PII Keys
+// =============================================================================
+
+// MarkPII tags keys matching the patterns as personal data. Patterns use
+// the same syntax as MarkSecret. PII values are not redacted; use the
+// ExcludePII and PIIOnly filters on exports, and EnablePIIAudit to track
+// reads.
+func (c *Config) MarkPII(patterns ...string) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.piiPatterns = append(c.piiPatterns, patterns...)
+	return c
+}
+
+// IsPII reports whether a key is tagged as PII.
+func (c *Config) IsPII(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isPII(key)
+}
+
+// isPII is IsPII for callers already holding c.mu.
+func (c *Config) isPII(key string) bool {
+	return matchesAny(c.piiPatterns, key)
+}
+
+// =============================================================================
+// Export Filters
+// =============================================================================
+
+// ExportFilter selects the keys included in an export.
+type ExportFilter func(v Value) bool
+
+var (
+	// ExcludePII drops PII-tagged keys.
+	ExcludePII ExportFilter = func(v Value) bool { return !v.IsPII() }
+
+	// PIIOnly keeps only PII-tagged keys.
+	PIIOnly ExportFilter = func(v Value) bool { return v.IsPII() }
+
+	// ExcludeSecrets drops secret keys rather than redacting them.
+	ExcludeSecrets ExportFilter = func(v Value) bool { return !v.IsSecret() }
+)
+
+// Export returns a flat copy of the configuration with secrets redacted,
+// keeping only keys accepted by every filter.
+func (c *Config) Export(filters ...ExportFilter) map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.export(c.data, filters)
+}
+
+// export returns the redacted entries of data accepted by filters; the
+// caller must hold c.mu.
+func (c *Config) export(data map[string]any, filters []ExportFilter) map[string]any {
+	out := make(map[string]any, len(data))
+	for k, raw := range data {
+		v := c.valueOf(k, raw)
+		if acceptAll(filters, v) {
+			out[k] = v.Redacted()
+		}
+	}
+	return out
+}
+
+func acceptAll(filters []ExportFilter, v Value) bool {
+	for _, f := range filters {
+		if !f(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// =============================================================================
+// PII Read Audit
+// =============================================================================
+
+// PIIAccess summarizes reads of one PII key by one reader. The reader is
+// the Go package that called into the Config, e.g. "example.com/app/billing".
+type PIIAccess struct {
+	Key    string
+	Reader string
+	Count  int
+	First  time.Time
+	Last   time.Time
+}
+
+// piiAudit records PII reads once enabled. It has its own lock because
+// reads happen under c.mu's read lock.
+type piiAudit struct {
+	enabled atomic.Bool
+	mu      sync.Mutex
+	reads   map[[2]string]*PIIAccess
+}
+
+// EnablePIIAudit starts recording which packages read PII-tagged keys
+// through Get*, Lookup and Bind. Identifying the caller costs a stack walk
+// per PII read, so auditing is off by default.
+func (c *Config) EnablePIIAudit() *Config {
+	c.pii.enabled.Store(true)
+	return c
+}
+
+// PIIAudit returns the recorded PII reads, sorted by key and reader.
+func (c *Config) PIIAudit() []PIIAccess {
+	c.pii.mu.Lock()
+	defer c.pii.mu.Unlock()
+
+	out := make([]PIIAccess, 0, len(c.pii.reads))
+	for _, a := range c.pii.reads {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Reader < out[j].Reader
+	})
+	return out
+}
+
+// auditRead records a read of key if it is PII; the caller must hold c.mu.
+func (c *Config) auditRead(key string) {
+	if c.pii.enabled.Load() && c.isPII(key) {
+		c.pii.record(key, callerPackage())
+	}
+}
+
+// auditBind records reads of the PII keys in data, a subtree bound from
+// prefix; the caller must hold c.mu.
+func (c *Config) auditBind(data map[string]any, prefix string) {
+	if !c.pii.enabled.Load() || len(c.piiPatterns) == 0 {
+		return
+	}
+	var reader string
+	for k := range data {
+		if k = joinKeys(prefix, k); c.isPII(k) {
+			if reader == "" {
+				reader = callerPackage()
+			}
+			c.pii.record(k, reader)
+		}
+	}
+}
+
+func (a *piiAudit) record(key, reader string) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.reads == nil {
+		a.reads = make(map[[2]string]*PIIAccess)
+	}
+	id := [2]string{key, reader}
+	access, ok := a.reads[id]
+	if !ok {
+		access = &PIIAccess{Key: key, Reader: reader, First: now}
+		a.reads[id] = access
+	}
+	access.Count++
+	access.Last = now
+}
+
+// thisPackage is the import path of this package, used to skip its frames.
+var thisPackage = reflect.TypeFor[Config]().PkgPath()
+
+// callerPackage returns the package of the first caller outside this one.
+func callerPackage() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if pkg := funcPackage(frame.Function); pkg != thisPackage && pkg != "" {
+			return pkg
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// funcPackage extracts the import path from a qualified function name such
+// as "example.com/app/billing.(*Service).Charge".
+func funcPackage(name string) string {
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] // type arguments of generic functions
+	}
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// =============================================================================
+// Pipeline Description
+// =============================================================================
+
+// Pipeline describes how a config loads: pre-load hooks, then sources in
+// load order (later sources override earlier ones), then post-load hooks.
+// Bind hooks run around Bind.
+type Pipeline struct {
+	PreLoad  []PipelineHook   `json:"pre_load,omitempty"`
+	Sources  []PipelineSource `json:"sources"`
+	PostLoad []PipelineHook   `json:"post_load,omitempty"`
+	PreBind  []PipelineHook   `json:"pre_bind,omitempty"`
+	PostBind []PipelineHook   `json:"post_bind,omitempty"`
+	Offline  bool             `json:"offline,omitempty"`
+}
+
+// PipelineSource describes a source. Middleware wraps a single source and
+// composites contain several; both list them in Wraps.
+type PipelineSource struct {
+	Name     string           `json:"name"`
+	Kind     string           `json:"kind"` // type name, e.g. "File", "Cached", "Composite"
+	Priority int              `json:"priority"`
+	Remote   bool             `json:"remote,omitempty"`
+	Wraps    []PipelineSource `json:"wraps,omitempty"`
+}
+
+// PipelineHook describes a hook in execution order.
+type PipelineHook struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// ExplainPipeline describes the config's load pipeline, for reviewing and
+// documenting builder setups. Render it with String, DOT or Mermaid.
+func (c *Config) ExplainPipeline() *Pipeline {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p := &Pipeline{
+		PreLoad:  describeHooks(c.hooks.preLoad),
+		PostLoad: describeHooks(c.hooks.postLoad),
+		PreBind:  describeHooks(c.hooks.preBind),
+		PostBind: describeHooks(c.hooks.postBind),
+		Offline:  c.offline,
+	}
+	for _, src := range c.sources {
+		p.Sources = append(p.Sources, describeSource(src))
+	}
+	return p
+}
+
+func describeHooks[H Hook](hooks []H) []PipelineHook {
+	out := make([]PipelineHook, len(hooks))
+	for i, h := range hooks {
+		out[i] = PipelineHook{Name: h.Name(), Priority: h.Priority()}
+	}
+	return out
+}
+
+func describeSource(src Source) PipelineSource {
+	ps := PipelineSource{Name: src.Name(), Kind: sourceKind(src), Priority: src.Priority()}
+	if r, ok := src.(RemoteSource); ok {
+		ps.Remote = r.Remote()
+	}
+	switch s := src.(type) {
+	case Unwrapper:
+		ps.Wraps = []PipelineSource{describeSource(s.Unwrap())}
+	case interface{ Sources() []Source }:
+		for _, child := range s.Sources() {
+			ps.Wraps = append(ps.Wraps, describeSource(child))
+		}
+	}
+	return ps
+}
+
+// sourceKind names a source by its type: "File" for *FileSource, "Cached"
+// for *middleware.Cached, and the package name for types named Source.
+func sourceKind(src Source) string {
+	t := reflect.TypeOf(src)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name, _, _ := strings.Cut(t.Name(), "[")
+	if kind := strings.TrimSuffix(name, "Source"); kind != "" {
+		return kind
+	}
+	if pkg := path.Base(t.PkgPath()); pkg != "." {
+		return pkg
+	}
+	return t.String()
+}
+
+// String renders the pipeline as an indented outline.
+func (p *Pipeline) String() string {
+	var b strings.Builder
+	hooks := func(stage string, hs []PipelineHook) {
+		if len(hs) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s hooks:\n", stage)
+		for i, h := range hs {
+			fmt.Fprintf(&b, "  %d. %s (priority %d)\n", i+1, h.Name, h.Priority)
+		}
+	}
+	var source func(s PipelineSource, indent string)
+	source = func(s PipelineSource, indent string) {
+		fmt.Fprintf(&b, "%s [%s, priority %d%s]\n", s.Name, s.Kind, s.Priority, p.remoteNote(s))
+		for _, w := range s.Wraps {
+			fmt.Fprintf(&b, "%s- ", indent)
+			source(w, indent+"  ")
+		}
+	}
+
+	hooks("pre-load", p.PreLoad)
+	b.WriteString("sources (later override earlier):\n")
+	for i, s := range p.Sources {
+		fmt.Fprintf(&b, "  %d. ", i+1)
+		source(s, "     ")
+	}
+	hooks("post-load", p.PostLoad)
+	hooks("pre-bind", p.PreBind)
+	hooks("post-bind", p.PostBind)
+	return b.String()
+}
+
+func (p *Pipeline) remoteNote(s PipelineSource) string {
+	switch {
+	case s.Remote && p.Offline:
+		return ", remote, offline"
+	case s.Remote:
+		return ", remote"
+	}
+	return ""
+}
+
+// =============================================================================
+// Graph Output
+// =============================================================================
+
+type graphNode struct {
+	id     string
+	label  []string // lines
+	kind   string   // "source", "hook" or "stage"
+	remote bool
+}
+
+type graphEdge struct {
+	from, to string
+	label    string
+}
+
+// graph lays the pipeline out as nodes and edges: pre-load hooks, then
+// each source tree feeding the merge, then post-load hooks producing the
+// config, then bind hooks.
+func (p *Pipeline) graph() ([]graphNode, []graphEdge) {
+	var nodes []graphNode
+	var edges []graphEdge
+	add := func(n graphNode) string {
+		n.id = fmt.Sprintf("n%d", len(nodes))
+		nodes = append(nodes, n)
+		return n.id
+	}
+	chain := func(prev, stage string, hs []PipelineHook) string {
+		for _, h := range hs {
+			id := add(graphNode{label: []string{h.Name, fmt.Sprintf("%s hook, priority %d", stage, h.Priority)}, kind: "hook"})
+			if prev != "" {
+				edges = append(edges, graphEdge{from: prev, to: id})
+			}
+			prev = id
+		}
+		return prev
+	}
+	var source func(s PipelineSource) string
+	source = func(s PipelineSource) string {
+		label := []string{s.Name, fmt.Sprintf("%s, priority %d", s.Kind, s.Priority)}
+		if s.Remote && p.Offline {
+			label = append(label, "offline")
+		}
+		id := add(graphNode{label: label, kind: "source", remote: s.Remote})
+		for _, w := range s.Wraps {
+			edges = append(edges, graphEdge{from: source(w), to: id})
+		}
+		return id
+	}
+
+	pre := chain("", "pre-load", p.PreLoad)
+	merge := add(graphNode{label: []string{"merge"}, kind: "stage"})
+	if pre != "" {
+		edges = append(edges, graphEdge{from: pre, to: merge, label: "then"})
+	}
+	for i, s := range p.Sources {
+		edges = append(edges, graphEdge{from: source(s), to: merge, label: fmt.Sprint(i + 1)})
+	}
+	last := chain(merge, "post-load", p.PostLoad)
+	cfg := add(graphNode{label: []string{"config"}, kind: "stage"})
+	edges = append(edges, graphEdge{from: last, to: cfg})
+	if len(p.PreBind)+len(p.PostBind) > 0 {
+		last = chain(cfg, "pre-bind", p.PreBind)
+		bind := add(graphNode{label: []string{"bind"}, kind: "stage"})
+		edges = append(edges, graphEdge{from: last, to: bind})
+		chain(bind, "post-bind", p.PostBind)
+	}
+	return nodes, edges
+}
+
+// DOT renders the pipeline as a Graphviz digraph. Remote sources are
+// drawn dashed.
+func (p *Pipeline) DOT() string {
+	nodes, edges := p.graph()
+	var b strings.Builder
+	b.WriteString("digraph pipeline {\n  rankdir=LR;\n")
+	for _, n := range nodes {
+		attrs := "shape=box"
+		switch n.kind {
+		case "hook":
+			attrs = "shape=ellipse"
+		case "stage":
+			attrs = "shape=box, style=\"rounded,bold\""
+		}
+		if n.remote {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, %s];\n", n.id, dotQuote(strings.Join(n.label, "\n")), attrs)
+	}
+	for _, e := range edges {
+		if e.label != "" {
+			fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", e.from, e.to, dotQuote(e.label))
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", e.from, e.to)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the pipeline as a Mermaid flowchart. Remote sources use
+// the "remote" class.
+func (p *Pipeline) Mermaid() string {
+	nodes, edges := p.graph()
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	var remote []string
+	for _, n := range nodes {
+		label := mermaidQuote(n.label)
+		switch n.kind {
+		case "hook":
+			fmt.Fprintf(&b, "  %s([%s])\n", n.id, label)
+		case "stage":
+			fmt.Fprintf(&b, "  %s((%s))\n", n.id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[%s]\n", n.id, label)
+		}
+		if n.remote {
+			remote = append(remote, n.id)
+		}
+	}
+	for _, e := range edges {
+		if e.label != "" {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", e.from, e.label, e.to)
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", e.from, e.to)
+		}
+	}
+	if len(remote) > 0 {
+		b.WriteString("  classDef remote stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&b, "  class %s remote\n", strings.Join(remote, ","))
+	}
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func mermaidQuote(lines []string) string {
+	r := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = r.Replace(l)
+	}
+	return `"` + strings.Join(out, "<br/>") + `"`
+}
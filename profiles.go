@@ -20,6 +20,16 @@ func NewProfileManager(config *Config) *ProfileManager {
 	}
 }
 
+// cloneFor copies the profiles and active selection for another Config.
+func (pm *ProfileManager) cloneFor(config *Config) *ProfileManager {
+	out := NewProfileManager(config)
+	for name, data := range pm.profiles {
+		out.profiles[name] = cloneMap(data)
+	}
+	out.active = pm.active
+	return out
+}
+
 // AddProfile adds a named configuration profile.
 func (pm *ProfileManager) AddProfile(name string, data map[string]any) {
 	pm.profiles[name] = cloneMap(data)
@@ -50,6 +60,7 @@ func (pm *ProfileManager) ListProfiles() []string {
 }
 
 // applyProfile applies a profile's data by adding it as a high-priority source.
+// Only the profile source is swapped; observers, hooks, and rules are kept.
 func (pm *ProfileManager) applyProfile(name string) error {
 	data, exists := pm.profiles[name]
 	if !exists {
@@ -59,6 +70,7 @@ func (pm *ProfileManager) applyProfile(name string) error {
 	// Create a temporary memory source with profile data at a very high priority.
 	// This ensures it overrides other sources.
 	source := MemoryWithPriority(data, 1000)
+	source.BaseSource = NewBaseSource("profile:"+name, 1000)
 
 	// We need to replace the old profile source if it exists.
 	pm.config.mu.Lock()
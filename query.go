@@ -0,0 +1,665 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Query
+// =============================================================================
+
+// Query evaluates a JSONPath expression against the nested view of the
+// configuration and returns every match. The leading "$" is optional:
+//
+//	cfg.Query("database.replicas[?(@.region=='eu')].host")
+//	cfg.Query("$..port")
+//	cfg.Query("servers[0:2].name")
+//
+// Supported: child (.a, ['a']), wildcard (*), recursive descent (..),
+// indexes and unions ([0], [-1], [0,2]), slices ([1:3]) and filters with
+// comparisons combined by && and || ([?(@.port > 8000 && @.tls)]).
+func (c *Config) Query(expr string) ([]any, error) {
+	steps, err := parseQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+
+	c.mu.RLock()
+	root := nestKeys(c.data)
+	c.mu.RUnlock()
+
+	return evalQuery([]any{root}, steps), nil
+}
+
+// QueryOne returns the first match of a JSONPath expression.
+func (c *Config) QueryOne(expr string) (any, bool, error) {
+	matches, err := c.Query(expr)
+	if err != nil || len(matches) == 0 {
+		return nil, false, err
+	}
+	return matches[0], true, nil
+}
+
+// =============================================================================
+// Nested View
+// =============================================================================
+
+// nestKeys rebuilds nested maps from dot keys. Nodes whose children are the
+// indexes 0..n-1 become lists; where a key has both a value and children
+// (the joined form of a list), the children win.
+func nestKeys(data map[string]any) map[string]any {
+	root := make(map[string]any)
+	keys := mapKeys(data)
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parts := splitPath(key)
+		node := root
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				if _, isMap := node[part].(map[string]any); !isMap {
+					node[part] = data[key]
+				}
+				break
+			}
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return listify(root).(map[string]any)
+}
+
+func listify(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, child := range m {
+		m[k] = listify(child)
+	}
+	if len(m) == 0 {
+		return m
+	}
+
+	list := make([]any, len(m))
+	for k, child := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		list[i] = child
+	}
+	return list
+}
+
+// =============================================================================
+// JSONPath Parsing
+// =============================================================================
+
+type queryStepKind int
+
+const (
+	stepChild queryStepKind = iota
+	stepWildcard
+	stepIndex
+	stepSlice
+	stepFilter
+)
+
+type queryStep struct {
+	kind      queryStepKind
+	recursive bool
+	names     []string
+	indexes   []int
+	slice     [3]*int
+	filter    queryExpr
+}
+
+func parseQuery(expr string) ([]queryStep, error) {
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+
+	var steps []queryStep
+	first := true
+	for len(s) > 0 {
+		recursive := false
+		switch {
+		case strings.HasPrefix(s, ".."):
+			recursive = true
+			s = s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] == '[':
+		case first:
+		default:
+			return nil, fmt.Errorf("unexpected %q", s)
+		}
+		first = false
+
+		var step queryStep
+		var err error
+		if strings.HasPrefix(s, "[") {
+			end := matchingBracket(s)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in %q", s)
+			}
+			step, err = parseBracket(s[1:end])
+			s = s[end+1:]
+		} else {
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			name := s[:end]
+			s = s[end:]
+			switch {
+			case name == "":
+				err = fmt.Errorf("empty name")
+			case strings.ContainsAny(name, " ()=<>!'\"&|?"):
+				err = fmt.Errorf("invalid name %q", name)
+			case name == "*":
+				step = queryStep{kind: stepWildcard}
+			default:
+				step = queryStep{kind: stepChild, names: []string{name}}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		step.recursive = recursive
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// matchingBracket returns the index of the ']' closing s[0], skipping
+// quoted strings and nested brackets.
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(body string) (queryStep, error) {
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "*":
+		return queryStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(body, "?"):
+		inner := strings.TrimSpace(body[1:])
+		if strings.HasPrefix(inner, "(") && strings.HasSuffix(inner, ")") {
+			inner = inner[1 : len(inner)-1]
+		}
+		filter, err := parseFilter(inner)
+		if err != nil {
+			return queryStep{}, err
+		}
+		return queryStep{kind: stepFilter, filter: filter}, nil
+	case strings.HasPrefix(body, "'") || strings.HasPrefix(body, `"`):
+		var names []string
+		for _, part := range splitUnquoted(body, ',') {
+			name, err := unquote(strings.TrimSpace(part))
+			if err != nil {
+				return queryStep{}, err
+			}
+			names = append(names, name)
+		}
+		return queryStep{kind: stepChild, names: names}, nil
+	case strings.Contains(body, ":"):
+		var step queryStep
+		step.kind = stepSlice
+		parts := strings.Split(body, ":")
+		if len(parts) > 3 {
+			return queryStep{}, fmt.Errorf("invalid slice %q", body)
+		}
+		for i, part := range parts {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return queryStep{}, fmt.Errorf("invalid slice %q", body)
+			}
+			step.slice[i] = &n
+		}
+		return step, nil
+	default:
+		var step queryStep
+		step.kind = stepIndex
+		for _, part := range strings.Split(body, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return queryStep{}, fmt.Errorf("invalid index %q", body)
+			}
+			step.indexes = append(step.indexes, n)
+		}
+		return step, nil
+	}
+}
+
+// splitUnquoted splits s on sep outside quotes and parentheses.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return unescape(s[1 : len(s)-1]), nil
+}
+
+// =============================================================================
+// Filter Expressions
+// =============================================================================
+
+// queryExpr is a filter expression: an OR of ANDs of comparisons.
+type queryExpr [][]queryComparison
+
+type queryComparison struct {
+	left, right queryOperand
+	op          string // empty for an existence test
+}
+
+type queryOperand struct {
+	path    []queryStep // relative to @ when isPath
+	isPath  bool
+	literal any
+}
+
+var queryOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(s string) (queryExpr, error) {
+	var expr queryExpr
+	for _, or := range splitOperator(s, "||") {
+		var and []queryComparison
+		for _, term := range splitOperator(or, "&&") {
+			cmp, err := parseComparison(strings.TrimSpace(term))
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, cmp)
+		}
+		expr = append(expr, and)
+	}
+	return expr, nil
+}
+
+// splitOperator splits s on a two-character operator outside quotes.
+func splitOperator(s, op string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], op):
+			parts = append(parts, s[start:i])
+			start = i + len(op)
+			i++
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseComparison(s string) (queryComparison, error) {
+	for _, op := range queryOperators {
+		parts := splitOperator(s, op)
+		if len(op) == 1 {
+			// Skip "<" and ">" that are part of "<=" or ">=".
+			parts = splitSingle(s, op[0])
+		}
+		if len(parts) == 2 {
+			left, err := parseOperand(strings.TrimSpace(parts[0]))
+			if err != nil {
+				return queryComparison{}, err
+			}
+			right, err := parseOperand(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return queryComparison{}, err
+			}
+			return queryComparison{left: left, right: right, op: op}, nil
+		}
+	}
+
+	operand, err := parseOperand(s)
+	if err != nil {
+		return queryComparison{}, err
+	}
+	return queryComparison{left: operand}, nil
+}
+
+// splitSingle splits on a one-character comparison not followed by '='.
+func splitSingle(s string, op byte) []string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == op && (i+1 >= len(s) || s[i+1] != '='):
+			return []string{s[:i], s[i+1:]}
+		}
+	}
+	return []string{s}
+}
+
+func parseOperand(s string) (queryOperand, error) {
+	switch {
+	case s == "@":
+		return queryOperand{isPath: true}, nil
+	case strings.HasPrefix(s, "@"):
+		steps, err := parseQuery(s[1:])
+		if err != nil {
+			return queryOperand{}, err
+		}
+		return queryOperand{isPath: true, path: steps}, nil
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		str, err := unquote(s)
+		return queryOperand{literal: str}, err
+	case s == "true":
+		return queryOperand{literal: true}, nil
+	case s == "false":
+		return queryOperand{literal: false}, nil
+	case s == "null":
+		return queryOperand{literal: nil}, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return queryOperand{literal: f}, nil
+	}
+	return queryOperand{}, fmt.Errorf("invalid operand %q", s)
+}
+
+func (e queryExpr) match(node any) bool {
+	for _, and := range e {
+		ok := true
+		for _, cmp := range and {
+			if !cmp.match(node) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (q queryComparison) match(node any) bool {
+	left, lok := q.left.resolve(node)
+	if q.op == "" {
+		return lok
+	}
+	right, rok := q.right.resolve(node)
+	if !lok || !rok {
+		return q.op == "!=" && lok != rok
+	}
+
+	lf, lnum := queryNumber(left)
+	rf, rnum := queryNumber(right)
+	if lnum && rnum {
+		switch q.op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+
+	ls, rs := fmt.Sprint(left), fmt.Sprint(right)
+	switch q.op {
+	case "==":
+		return ls == rs
+	case "!=":
+		return ls != rs
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+	return false
+}
+
+func (o queryOperand) resolve(node any) (any, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+	matches := evalQuery([]any{node}, o.path)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[0], true
+}
+
+// =============================================================================
+// Evaluation
+// =============================================================================
+
+func evalQuery(nodes []any, steps []queryStep) []any {
+	for _, step := range steps {
+		if step.recursive {
+			nodes = descendants(nodes)
+		}
+		var next []any
+		for _, node := range nodes {
+			next = append(next, step.apply(node)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (s queryStep) apply(node any) []any {
+	switch s.kind {
+	case stepChild:
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		var out []any
+		for _, name := range s.names {
+			if v, ok := m[name]; ok {
+				out = append(out, v)
+			}
+		}
+		return out
+	case stepWildcard:
+		return children(node)
+	case stepIndex:
+		list, ok := node.([]any)
+		if !ok {
+			return nil
+		}
+		var out []any
+		for _, i := range s.indexes {
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				out = append(out, list[i])
+			}
+		}
+		return out
+	case stepSlice:
+		list, ok := node.([]any)
+		if !ok {
+			return nil
+		}
+		return sliceList(list, s.slice)
+	case stepFilter:
+		var out []any
+		for _, child := range children(node) {
+			if s.filter.match(child) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// children returns list elements, or map values in key order.
+func children(node any) []any {
+	switch x := node.(type) {
+	case []any:
+		return x
+	case map[string]any:
+		keys := mapKeys(x)
+		sort.Strings(keys)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = x[k]
+		}
+		return out
+	}
+	return nil
+}
+
+// descendants returns the nodes and everything below them, depth first.
+func descendants(nodes []any) []any {
+	var out []any
+	var walk func(any)
+	walk = func(n any) {
+		out = append(out, n)
+		for _, child := range children(n) {
+			walk(child)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return out
+}
+
+func sliceList(list []any, bounds [3]*int) []any {
+	n := len(list)
+	step := 1
+	if bounds[2] != nil && *bounds[2] != 0 {
+		step = *bounds[2]
+	}
+
+	clamp := func(i int) int {
+		if i < 0 {
+			i += n
+		}
+		return max(0, min(i, n))
+	}
+	start, end := 0, n
+	if step < 0 {
+		start, end = n-1, -1
+	}
+	if bounds[0] != nil {
+		start = clamp(*bounds[0])
+	}
+	if bounds[1] != nil {
+		end = clamp(*bounds[1])
+	}
+
+	var out []any
+	if step > 0 {
+		for i := start; i < end; i += step {
+			out = append(out, list[i])
+		}
+	} else {
+		for i := min(start, n-1); i > end; i += step {
+			out = append(out, list[i])
+		}
+	}
+	return out
+}
+
+func queryNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Snapshot Recording
+// =============================================================================
+
+// RecordedSnapshot is one loaded configuration captured by a Recorder.
+type RecordedSnapshot struct {
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data"`
+}
+
+// Recorder is a post-load hook that appends every loaded snapshot, with its
+// timestamp, to a JSON-lines file for later replay. Secret values are
+// redacted unless IncludeSecrets is called.
+type Recorder struct {
+	mu             sync.Mutex
+	file           *os.File
+	includeSecrets bool
+}
+
+// NewRecorder opens (or creates) path for appending snapshots.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open recording: %w", err)
+	}
+	return &Recorder{file: f}, nil
+}
+
+// IncludeSecrets records secret values verbatim.
+func (r *Recorder) IncludeSecrets() *Recorder {
+	r.includeSecrets = true
+	return r
+}
+
+func (r *Recorder) Name() string  { return "recorder" }
+func (r *Recorder) Priority() int { return 2000 } // After all other hooks
+
+// OnPostLoad appends the snapshot. It runs while the config is locked, so it
+// redacts without going through the locking helpers.
+func (r *Recorder) OnPostLoad(c *Config, data map[string]any) error {
+	snap := RecordedSnapshot{Time: time.Now(), Data: make(map[string]any, len(data))}
+	for k, v := range data {
+		if !r.includeSecrets {
+			v = c.redact(k, v)
+		}
+		snap.Data[k] = v
+	}
+
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// ReadRecording reads snapshots written by a Recorder. Numbers are decoded
+// as json.Number so integers keep their exact value.
+func ReadRecording(path string) ([]RecordedSnapshot, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read recording: %w", err)
+	}
+
+	var out []RecordedSnapshot
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(raw)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.UseNumber()
+		var snap RecordedSnapshot
+		if err := dec.Decode(&snap); err != nil {
+			return nil, fmt.Errorf("recording line %d: %w", line, err)
+		}
+		out = append(out, snap)
+	}
+	return out, scanner.Err()
+}
+
+// =============================================================================
+// Replay
+// =============================================================================
+
+// Replayer feeds recorded snapshots back through a Config via its Source,
+// reproducing the original reload sequence deterministically.
+type Replayer struct {
+	mu        sync.Mutex
+	snapshots []RecordedSnapshot
+	pos       int
+	source    *ReplaySource
+}
+
+// ReplaySource serves the replayer's current snapshot.
+type ReplaySource struct {
+	BaseSource
+	replayer *Replayer
+}
+
+// NewReplayer creates a replayer positioned before the first snapshot.
+func NewReplayer(snapshots []RecordedSnapshot) *Replayer {
+	r := &Replayer{snapshots: snapshots, pos: -1}
+	r.source = &ReplaySource{BaseSource: NewBaseSource("replay", DefaultMemoryPriority), replayer: r}
+	return r
+}
+
+// Source returns the source to add to the Config under test.
+func (r *Replayer) Source() *ReplaySource {
+	return r.source
+}
+
+// Load returns the current snapshot (empty before the first step).
+func (s *ReplaySource) Load() (map[string]any, error) {
+	s.replayer.mu.Lock()
+	defer s.replayer.mu.Unlock()
+	if s.replayer.pos < 0 {
+		return make(map[string]any), nil
+	}
+	return deepCloneMap(s.replayer.snapshots[s.replayer.pos].Data), nil
+}
+
+// Step advances to the next snapshot and reloads c. It returns false once
+// all snapshots have been replayed.
+func (r *Replayer) Step(c *Config) (bool, error) {
+	r.mu.Lock()
+	if r.pos+1 >= len(r.snapshots) {
+		r.mu.Unlock()
+		return false, nil
+	}
+	r.pos++
+	r.mu.Unlock()
+	return true, c.Load()
+}
+
+// Run replays every snapshot, preserving the recorded gaps divided by speed
+// (speed <= 0 replays without waiting). Load errors are collected and
+// returned together after the run.
+func (r *Replayer) Run(ctx context.Context, c *Config, speed float64) error {
+	var errs []error
+	for i := range r.snapshots {
+		if i > 0 && speed > 0 {
+			gap := r.snapshots[i].Time.Sub(r.snapshots[i-1].Time)
+			select {
+			case <-time.After(time.Duration(float64(gap) / speed)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if _, err := r.Step(c); err != nil {
+			errs = append(errs, fmt.Errorf("snapshot %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Reload Orchestration
+// =============================================================================
+
+// ReloadStage names a step of a ReloadPlan.
+type ReloadStage string
+
+const (
+	StageDrain    ReloadStage = "drain"
+	StageApply    ReloadStage = "apply"
+	StageVerify   ReloadStage = "verify"
+	StageRollback ReloadStage = "rollback"
+)
+
+// DefaultStageTimeout bounds plan stages that set no timeout of their own.
+const DefaultStageTimeout = 30 * time.Second
+
+// DefaultStageGrace is how long a timed-out stage may take to return when
+// a plan sets no StageGrace.
+const DefaultStageGrace = 5 * time.Second
+
+// ReloadPlan turns a change set into an operational procedure, e.g.
+// restarting a sub-process: drain traffic, apply the change, verify the
+// result, and roll back if any of those fail. Nil stages are skipped.
+// Each stage gets a context that is cancelled at its timeout. The plan then
+// waits up to StageGrace for the stage to return, so Rollback never runs
+// alongside it, and judges the stage by what it returned; a stage still
+// running after that is abandoned, fails, and Rollback is skipped.
+type ReloadPlan struct {
+	Name string
+	Keys []string // keys, their subtrees or key patterns (see Rules) triggering the plan; empty matches any change
+
+	Drain    func(ctx context.Context, cs *ChangeSet) error
+	Apply    func(ctx context.Context, cs *ChangeSet) error
+	Verify   func(ctx context.Context, cs *ChangeSet) error
+	Rollback func(ctx context.Context, cs *ChangeSet, cause error) error // cs.Previous holds the values to restore
+
+	Timeouts   map[ReloadStage]time.Duration // per stage, defaulting to DefaultStageTimeout
+	StageGrace time.Duration                 // wait for a timed-out stage to return, defaulting to DefaultStageGrace
+
+	// AbortOnFailure stops delivery of a failed change set to observers
+	// with a higher priority value.
+	AbortOnFailure bool
+}
+
+// StageResult reports how a stage went.
+type StageResult struct {
+	Stage     ReloadStage
+	Duration  time.Duration
+	Err       error
+	Abandoned bool // still running after its timeout and grace period
+}
+
+// ReloadResult reports a plan run.
+type ReloadResult struct {
+	Plan       string
+	Stages     []StageResult
+	Err        error // first failing stage's error, nil on success
+	RolledBack bool  // Rollback ran and succeeded
+	At         time.Time
+}
+
+// ReloadOrchestrator runs a ReloadPlan for each matching change set. It is
+// a ChangeSetObserver; register it with Orchestrate or ObserveChanges.
+// Each stage emits an EventReloadStage event, and failures are passed to
+// the config's error handler.
+type ReloadOrchestrator struct {
+	config *Config
+	plan   ReloadPlan
+
+	mu   sync.Mutex
+	last *ReloadResult
+}
+
+// NewReloadOrchestrator returns an orchestrator running plan for c.
+func NewReloadOrchestrator(c *Config, plan ReloadPlan) *ReloadOrchestrator {
+	if plan.Name == "" {
+		plan.Name = "reload"
+	}
+	return &ReloadOrchestrator{config: c, plan: plan}
+}
+
+// Orchestrate registers an orchestrator running plan on changes and
+// returns it.
+func (c *Config) Orchestrate(plan ReloadPlan, opts ...ObserverOption) *ReloadOrchestrator {
+	o := NewReloadOrchestrator(c, plan)
+	c.ObserveChanges(o, opts...)
+	return o
+}
+
+// OnChangeSet runs the plan if the change set touches its keys.
+func (o *ReloadOrchestrator) OnChangeSet(cs *ChangeSet) {
+	if !o.matches(cs) {
+		return
+	}
+	res := o.Run(o.config.ctx, cs)
+	if res.Err != nil {
+		o.config.handleError(fmt.Errorf("reload plan %s: %w", o.plan.Name, res.Err))
+		if o.plan.AbortOnFailure {
+			cs.Abort()
+		}
+	}
+}
+
+// Run executes the plan for cs and returns the result. Cancelling ctx
+// cancels the running stage.
+func (o *ReloadOrchestrator) Run(ctx context.Context, cs *ChangeSet) *ReloadResult {
+	res := &ReloadResult{Plan: o.plan.Name, At: time.Now()}
+	for _, step := range []struct {
+		stage ReloadStage
+		fn    func(ctx context.Context, cs *ChangeSet) error
+	}{
+		{StageDrain, o.plan.Drain},
+		{StageApply, o.plan.Apply},
+		{StageVerify, o.plan.Verify},
+	} {
+		if step.fn == nil {
+			continue
+		}
+		err := o.stage(ctx, res, step.stage, func(ctx context.Context) error {
+			return step.fn(ctx, cs)
+		})
+		if err != nil {
+			res.Err = fmt.Errorf("%s: %w", step.stage, err)
+			break
+		}
+	}
+
+	if res.Err != nil && o.plan.Rollback != nil {
+		if last := res.Stages[len(res.Stages)-1]; last.Abandoned {
+			res.Err = errors.Join(res.Err, fmt.Errorf("rollback skipped: %s still running", last.Stage))
+		} else {
+			cause := res.Err
+			err := o.stage(ctx, res, StageRollback, func(ctx context.Context) error {
+				return o.plan.Rollback(ctx, cs, cause)
+			})
+			if err != nil {
+				res.Err = errors.Join(res.Err, fmt.Errorf("rollback: %w", err))
+			} else {
+				res.RolledBack = true
+			}
+		}
+	}
+
+	o.mu.Lock()
+	o.last = res
+	o.mu.Unlock()
+	return res
+}
+
+// LastResult returns the result of the latest run, or nil before the
+// first one.
+func (o *ReloadOrchestrator) LastResult() *ReloadResult {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.last
+}
+
+// stage runs fn under the stage's timeout, records the result and emits
+// an event. After a timeout it waits up to the grace period for fn's
+// result, and fails the stage as abandoned if it does not return.
+func (o *ReloadOrchestrator) stage(ctx context.Context, res *ReloadResult, stage ReloadStage, fn func(ctx context.Context) error) error {
+	timeout := o.plan.Timeouts[stage]
+	if timeout <= 0 {
+		timeout = DefaultStageTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	var err error
+	abandoned := false
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		grace := o.plan.StageGrace
+		if grace <= 0 {
+			grace = DefaultStageGrace
+		}
+		wait := time.NewTimer(grace)
+		select {
+		case err = <-done:
+		case <-wait.C:
+			err, abandoned = ctx.Err(), true
+		}
+		wait.Stop()
+	}
+
+	sr := StageResult{Stage: stage, Duration: time.Since(started), Err: err, Abandoned: abandoned}
+	res.Stages = append(res.Stages, sr)
+	o.config.events.emit(Event{Type: EventReloadStage, Source: o.plan.Name, Stage: stage, Duration: sr.Duration, Err: err})
+	return err
+}
+
+func (o *ReloadOrchestrator) matches(cs *ChangeSet) bool {
+	if len(o.plan.Keys) == 0 {
+		return true
+	}
+	for k := range cs.Changed {
+		for _, pattern := range o.plan.Keys {
+			if k == pattern || strings.HasPrefix(k, pattern+".") || matchKeyPattern(pattern, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Load Reports
+// =============================================================================
+
+// Source load statuses in a SourceReport.
+const (
+	SourceOK     = "ok"
+	SourceFailed = "failed"
+)
+
+// SourceReport describes how one source fared during a load.
+type SourceReport struct {
+	Name      string
+	Priority  int
+	Status    string // SourceOK, SourceFailed, SourceCached or SourceOffline
+	Duration  time.Duration
+	Keys      int
+	Err       error
+	Conflicts []SourceConflict // resolved by composites using ConflictReport or ConflictFirstWins
+}
+
+// LoadReport summarizes a load: what each source contributed, which hooks
+// ran, which keys came from defaults, how many rules were evaluated, and
+// anything worth a warning. It is meant to be logged as one structured line
+// at startup.
+type LoadReport struct {
+	StartedAt      time.Time
+	Duration       time.Duration
+	Sources        []SourceReport
+	Hooks          []string           // hooks executed, in order
+	Defaults       []string           // keys filled in by a defaults hook
+	Migrations     []AppliedMigration // schema migrations applied to sources
+	RulesEvaluated int                // keys checked against validation rules
+	Keys           int                // keys loaded
+	Changed        int                // keys changed by this load
+	Warnings       []string
+	Err            error
+}
+
+// OK reports whether the load succeeded.
+func (r *LoadReport) OK() bool { return r.Err == nil }
+
+// String renders the report as a single log line.
+func (r *LoadReport) String() string {
+	var b strings.Builder
+	status := "ok"
+	if r.Err != nil {
+		status = "failed: " + r.Err.Error()
+	}
+	fmt.Fprintf(&b, "config load %s in %s: %d keys (%d changed)", status, r.Duration.Round(time.Microsecond), r.Keys, r.Changed)
+
+	parts := make([]string, len(r.Sources))
+	for i, s := range r.Sources {
+		parts[i] = fmt.Sprintf("%s=%s/%d/%s", s.Name, s.Status, s.Keys, s.Duration.Round(time.Microsecond))
+	}
+	fmt.Fprintf(&b, "; sources [%s]", strings.Join(parts, " "))
+	if len(r.Hooks) > 0 {
+		fmt.Fprintf(&b, "; hooks [%s]", strings.Join(r.Hooks, " "))
+	}
+	if len(r.Defaults) > 0 {
+		fmt.Fprintf(&b, "; %d defaults", len(r.Defaults))
+	}
+	if len(r.Migrations) > 0 {
+		fmt.Fprintf(&b, "; migrated [%s]", strings.Join(describeMigrations(r.Migrations), " "))
+	}
+	if r.RulesEvaluated > 0 {
+		fmt.Fprintf(&b, "; %d rules evaluated", r.RulesEvaluated)
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(&b, "; warnings [%s]", strings.Join(r.Warnings, "; "))
+	}
+	return b.String()
+}
+
+// LogValue renders the report as a structured slog group, so
+// slog.Info("config loaded", "report", report) logs one rich line.
+func (r *LoadReport) LogValue() slog.Value {
+	sources := make([]slog.Attr, len(r.Sources))
+	for i, s := range r.Sources {
+		attrs := []slog.Attr{
+			slog.String("status", s.Status),
+			slog.Int("keys", s.Keys),
+			slog.Duration("duration", s.Duration),
+		}
+		if s.Err != nil {
+			attrs = append(attrs, slog.String("error", s.Err.Error()))
+		}
+		sources[i] = slog.Attr{Key: s.Name, Value: slog.GroupValue(attrs...)}
+	}
+
+	attrs := []slog.Attr{
+		slog.Bool("ok", r.OK()),
+		slog.Duration("duration", r.Duration),
+		slog.Int("keys", r.Keys),
+		slog.Int("changed", r.Changed),
+		slog.Attr{Key: "sources", Value: slog.GroupValue(sources...)},
+		slog.Any("hooks", r.Hooks),
+		slog.Int("defaults", len(r.Defaults)),
+		slog.Int("rules", r.RulesEvaluated),
+	}
+	if len(r.Migrations) > 0 {
+		attrs = append(attrs, slog.Any("migrations", describeMigrations(r.Migrations)))
+	}
+	if len(r.Warnings) > 0 {
+		attrs = append(attrs, slog.Any("warnings", r.Warnings))
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LastLoadReport returns the report of the most recent load, or nil before
+// the first one.
+func (c *Config) LastLoadReport() *LoadReport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.report
+}
+
+// Reload loads all sources again and returns the load's report. The report
+// is returned even when the load fails.
+func (c *Config) Reload() (*LoadReport, error) {
+	err := c.Load()
+	return c.LastLoadReport(), err
+}
+
+// sourceConflicts returns the conflicts resolved by composites within src.
+func sourceConflicts(src Source) []SourceConflict {
+	var out []SourceConflict
+	walkSources(src, func(s Source) {
+		if cs, ok := s.(interface{ Conflicts() []SourceConflict }); ok {
+			out = append(out, cs.Conflicts()...)
+		}
+	})
+	return out
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// RPC Hook Bridge
+// =============================================================================
+
+// Hook bridge events.
+const (
+	HookEventPostLoad  = "post_load"
+	HookEventChangeSet = "change_set"
+)
+
+// HookRequest is sent to an external hook for each event. PostLoad requests
+// carry the full flat key map; ChangeSet requests carry the keys that are
+// about to change and their previous values.
+type HookRequest struct {
+	ID       uint64         `json:"id"`
+	Event    string         `json:"event"`
+	Data     map[string]any `json:"data,omitempty"`
+	Changed  map[string]any `json:"changed,omitempty"`
+	Previous map[string]any `json:"previous,omitempty"`
+}
+
+// HookResponse is an external hook's answer. Set and Delete mutate the
+// loaded data; a non-empty Veto rejects the load and keeps the current
+// configuration.
+type HookResponse struct {
+	ID     uint64         `json:"id"`
+	Set    map[string]any `json:"set,omitempty"`
+	Delete []string       `json:"delete,omitempty"`
+	Veto   string         `json:"veto,omitempty"`
+}
+
+// HookTransport delivers a request to an external process. ProcessTransport
+// speaks line-delimited JSON over stdin/stdout; implement this interface to
+// bridge gRPC or another protocol.
+type HookTransport interface {
+	Call(ctx context.Context, req *HookRequest) (*HookResponse, error)
+}
+
+// HookTransportFunc adapts a function to HookTransport.
+type HookTransportFunc func(ctx context.Context, req *HookRequest) (*HookResponse, error)
+
+func (f HookTransportFunc) Call(ctx context.Context, req *HookRequest) (*HookResponse, error) {
+	return f(ctx, req)
+}
+
+// VetoError is returned when an external hook vetoes a load.
+type VetoError struct {
+	Hook   string
+	Event  string
+	Reason string
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("%s vetoed %s: %s", e.Hook, e.Event, e.Reason)
+}
+
+// RPCHookOptions configure an RPC hook.
+type RPCHookOptions struct {
+	Name     string        // defaults to "rpc"
+	Priority int           // hook priority, defaults to 60
+	Timeout  time.Duration // per-call limit, defaults to 5s
+
+	// FailOpen keeps loading when the transport fails, reporting the error
+	// to the error handler instead. Vetoes are always honoured.
+	FailOpen bool
+}
+
+// RPCHook lets cross-language policy engines take part in loading. After
+// the sources are merged it sends a PostLoad request and applies the
+// returned mutations, then, if any keys would change, a ChangeSet request.
+// Either response may veto the load.
+type RPCHook struct {
+	transport HookTransport
+	opts      RPCHookOptions
+
+	mu     sync.Mutex
+	nextID uint64
+}
+
+func NewRPCHook(transport HookTransport, opts RPCHookOptions) *RPCHook {
+	if opts.Name == "" {
+		opts.Name = "rpc"
+	}
+	if opts.Priority == 0 {
+		opts.Priority = 60
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &RPCHook{transport: transport, opts: opts}
+}
+
+func (h *RPCHook) Name() string  { return h.opts.Name }
+func (h *RPCHook) Priority() int { return h.opts.Priority }
+
+// OnPostLoad runs while Load holds the config lock, so c.data is still the
+// configuration currently in effect.
+func (h *RPCHook) OnPostLoad(c *Config, data map[string]any) error {
+	if err := h.call(c, data, &HookRequest{Event: HookEventPostLoad, Data: cloneMap(data)}); err != nil {
+		return err
+	}
+
+	changed := detectChanges(c.data, data)
+	if len(changed) == 0 {
+		return nil
+	}
+	previous := make(map[string]any, len(changed))
+	for k := range changed {
+		if old, ok := c.data[k]; ok {
+			previous[k] = old
+		}
+	}
+	return h.call(c, data, &HookRequest{Event: HookEventChangeSet, Changed: changed, Previous: previous})
+}
+
+// Close closes the transport if it holds resources.
+func (h *RPCHook) Close() error {
+	if closer, ok := h.transport.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (h *RPCHook) call(c *Config, data map[string]any, req *HookRequest) error {
+	h.mu.Lock()
+	h.nextID++
+	req.ID = h.nextID
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.opts.Timeout)
+	defer cancel()
+
+	resp, err := h.transport.Call(ctx, req)
+	if err == nil && resp.ID != req.ID {
+		err = fmt.Errorf("response id %d does not match request %d", resp.ID, req.ID)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s %s: %w", h.opts.Name, req.Event, err)
+		if h.opts.FailOpen {
+			c.handleError(err)
+			return nil
+		}
+		return err
+	}
+
+	if resp.Veto != "" {
+		return &VetoError{Hook: h.opts.Name, Event: req.Event, Reason: resp.Veto}
+	}
+	for _, k := range resp.Delete {
+		delete(data, k)
+	}
+	flatten("", resp.Set, data)
+	return nil
+}
+
+// =============================================================================
+// Process Transport
+// =============================================================================
+
+// ProcessTransport runs a long-lived helper and exchanges one JSON line per
+// request and response over its stdin and stdout. The helper is started on
+// the first call and restarted after it exits or a call fails.
+type ProcessTransport struct {
+	command string
+	args    []string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte
+	quit   chan struct{} // closed by stop
+	exited chan struct{} // closed once the helper has been reaped
+}
+
+func NewProcessTransport(command string, args ...string) *ProcessTransport {
+	return &ProcessTransport{command: command, args: args}
+}
+
+// Call sends req and waits for the matching response line.
+func (t *ProcessTransport) Call(ctx context.Context, req *HookRequest) (*HookResponse, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cmd == nil {
+		if err := t.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	if _, err := t.stdin.Write(append(line, '\n')); err != nil {
+		t.stop()
+		return nil, fmt.Errorf("write request: %w", err)
+	}
+
+	select {
+	case raw := <-t.lines:
+		var resp HookResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			t.stop()
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		return &resp, nil
+	case <-t.exited:
+		t.stop()
+		return nil, fmt.Errorf("hook process %s exited", t.command)
+	case <-ctx.Done():
+		// A late answer would be read as the next response.
+		t.stop()
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the helper.
+func (t *ProcessTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stop()
+	return nil
+}
+
+func (t *ProcessTransport) start() error {
+	cmd := exec.Command(t.command, t.args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("hook process %s: %w", t.command, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("hook process %s: %w", t.command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("hook process %s: %w", t.command, err)
+	}
+
+	lines := make(chan []byte)
+	quit := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		defer cmd.Wait()
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	t.cmd, t.stdin, t.lines, t.quit, t.exited = cmd, stdin, lines, quit, exited
+	return nil
+}
+
+func (t *ProcessTransport) stop() {
+	if t.cmd == nil {
+		return
+	}
+	close(t.quit)
+	_ = t.stdin.Close()
+	_ = t.cmd.Process.Kill()
+	<-t.exited
+	t.cmd = nil
+}
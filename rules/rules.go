@@ -0,0 +1,323 @@
+// Package rules builds validator rules for config keys: fluent rule
+// construction, named rule sets and wildcard key patterns. Tags follow
+// go-playground/validator v10 syntax, but the package does not import it.
+package rules
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// =============================================================================
+// Validator Tag Constants (go-playground/validator v10 compatible)
+// =============================================================================
+
+const (
+	TagRequired = "required"
+
+	TagMin = "min"
+	TagMax = "max"
+
+	TagGT  = "gt"
+	TagLT  = "lt"
+	TagGTE = "gte"
+	TagLTE = "lte"
+
+	TagEQ = "eq"
+	TagNE = "ne"
+
+	TagEmail = "email"
+	TagURL   = "url"
+	TagUUID  = "uuid"
+	TagUUID4 = "uuid4"
+
+	TagLen    = "len"
+	TagOneOf  = "oneof"
+	TagRegexp = "regexp"
+
+	TagDive = "dive"
+)
+
+// =============================================================================
+// Fluent Validation Rules
+// =============================================================================
+
+// Rule is a chainable set of validator rules for a config key.
+type Rule struct {
+	key     string
+	tags    []string
+	message string
+}
+
+// New starts an empty rule for key.
+func New(key string) *Rule {
+	return &Rule{key: key}
+}
+
+// Add appends a validator tag (optionally with a parameter).
+func (v *Rule) Add(tag, param string) *Rule {
+	if param != "" {
+		v.tags = append(v.tags, tag+"="+param)
+	} else {
+		v.tags = append(v.tags, tag)
+	}
+	return v
+}
+
+// Dive makes the tags added after it apply to each element of a list
+// instead of the list itself, like validator's dive:
+//
+//	Rules.Required("kafka.brokers").Add("min", "1").Dive().Add("hostname_port", "")
+func (v *Rule) Dive() *Rule {
+	return v.Add(TagDive, "")
+}
+
+// Dives reports whether the rule validates list elements.
+func (v *Rule) Dives() bool {
+	for _, tag := range v.tags {
+		for _, t := range strings.Split(tag, ",") {
+			if t == TagDive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Message sets a custom error message reported instead of the generic one.
+func (v *Rule) Message(msg string) *Rule {
+	v.message = msg
+	return v
+}
+
+// String converts the rule set into a validator-compatible tag string.
+func (v *Rule) String() string {
+	return strings.Join(v.tags, ",")
+}
+
+// Key returns the config key the rules apply to.
+func (v *Rule) Key() string {
+	return v.key
+}
+
+// CustomMessage returns the message set with Message, if any.
+func (v *Rule) CustomMessage() string {
+	return v.message
+}
+
+// =============================================================================
+// Rules Factory Methods
+// =============================================================================
+
+var Rules = struct {
+	Required func(key string) *Rule
+	Range    func(key string, min, max int) *Rule
+	Min      func(key string, min int) *Rule
+	Max      func(key string, max int) *Rule
+	Email    func(key string) *Rule
+	URL      func(key string) *Rule
+	UUID     func(key string, version ...int) *Rule
+	Len      func(key string, length int) *Rule
+	OneOf    func(key string, values ...string) *Rule
+	Pattern  func(key, pattern string) *Rule
+	Gt       func(key string, value any) *Rule
+	Lt       func(key string, value any) *Rule
+	Gte      func(key string, value any) *Rule
+	Lte      func(key string, value any) *Rule
+	Eq       func(key string, value any) *Rule
+	Ne       func(key string, value any) *Rule
+	V10      func(key, tag string, param ...string) *Rule
+	Each     func(key string) *Rule
+}{
+	Required: func(key string) *Rule {
+		return New(key).Add(TagRequired, "")
+	},
+
+	Range: func(key string, min, max int) *Rule {
+		return New(key).
+			Add(TagMin, fmt.Sprint(min)).
+			Add(TagMax, fmt.Sprint(max))
+	},
+
+	Min: func(key string, min int) *Rule {
+		return New(key).Add(TagMin, fmt.Sprint(min))
+	},
+
+	Max: func(key string, max int) *Rule {
+		return New(key).Add(TagMax, fmt.Sprint(max))
+	},
+
+	Email: func(key string) *Rule {
+		return New(key).Add(TagEmail, "")
+	},
+
+	URL: func(key string) *Rule {
+		return New(key).Add(TagURL, "")
+	},
+
+	UUID: func(key string, version ...int) *Rule {
+		r := New(key)
+		if len(version) > 0 && version[0] == 4 {
+			return r.Add(TagUUID4, "")
+		}
+		return r.Add(TagUUID, "")
+	},
+
+	Len: func(key string, length int) *Rule {
+		return New(key).Add(TagLen, fmt.Sprint(length))
+	},
+
+	OneOf: func(key string, values ...string) *Rule {
+		return New(key).Add(TagOneOf, strings.Join(values, " "))
+	},
+
+	Pattern: func(key, pattern string) *Rule {
+		return New(key).Add(TagRegexp, pattern)
+	},
+
+	Gt: func(key string, value any) *Rule {
+		return New(key).Add(TagGT, fmt.Sprint(value))
+	},
+
+	Lt: func(key string, value any) *Rule {
+		return New(key).Add(TagLT, fmt.Sprint(value))
+	},
+
+	Gte: func(key string, value any) *Rule {
+		return New(key).Add(TagGTE, fmt.Sprint(value))
+	},
+
+	Lte: func(key string, value any) *Rule {
+		return New(key).Add(TagLTE, fmt.Sprint(value))
+	},
+
+	Eq: func(key string, value any) *Rule {
+		return New(key).Add(TagEQ, fmt.Sprint(value))
+	},
+
+	Ne: func(key string, value any) *Rule {
+		return New(key).Add(TagNE, fmt.Sprint(value))
+	},
+
+	V10: func(key, tag string, param ...string) *Rule {
+		r := New(key)
+		if len(param) > 0 {
+			return r.Add(tag, param[0])
+		}
+		return r.Add(tag, "")
+	},
+
+	Each: func(key string) *Rule {
+		return New(key).Dive()
+	},
+}
+
+// =============================================================================
+// Rule Sets
+// =============================================================================
+
+var (
+	ruleSetsMu sync.RWMutex
+	ruleSets   = make(map[string][]*Rule)
+)
+
+// RegisterSet registers a reusable bundle of rules under a name. Rule keys
+// are relative and get prefixed when the set is looked up.
+// Registering the same name again replaces the previous bundle.
+func RegisterSet(name string, rules ...*Rule) {
+	ruleSetsMu.Lock()
+	defer ruleSetsMu.Unlock()
+	ruleSets[name] = append([]*Rule(nil), rules...)
+}
+
+// LookupSet returns the rules of a named set re-keyed under prefix.
+func LookupSet(prefix, name string) ([]*Rule, error) {
+	ruleSetsMu.RLock()
+	set, ok := ruleSets[name]
+	ruleSetsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("rule set %q is not registered", name)
+	}
+
+	out := make([]*Rule, len(set))
+	for i, r := range set {
+		out[i] = r.WithPrefix(prefix)
+	}
+	return out, nil
+}
+
+// WithPrefix returns a copy of the rule with the key nested under prefix.
+func (v *Rule) WithPrefix(prefix string) *Rule {
+	cp := *v
+	if prefix != "" {
+		cp.key = prefix + "." + v.key
+	}
+	cp.tags = append([]string(nil), v.tags...)
+	return &cp
+}
+
+// =============================================================================
+// Key Patterns
+// =============================================================================
+
+// IsPattern reports whether a rule key contains glob segments.
+func IsPattern(key string) bool {
+	return strings.ContainsAny(key, "*?[")
+}
+
+// MatchPattern matches a dotted key against a pattern segment by segment;
+// "*" matches exactly one segment.
+func MatchPattern(pattern, key string) bool {
+	pp, kp := strings.Split(pattern, "."), strings.Split(key, ".")
+	if len(pp) != len(kp) {
+		return false
+	}
+	return matchSegments(pp, kp)
+}
+
+func matchSegments(pattern, key []string) bool {
+	for i, seg := range pattern {
+		if ok, err := path.Match(seg, key[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ExpandPattern resolves a pattern into the concrete keys it covers.
+// Wildcards are bound from existing keys; trailing literal segments are kept,
+// so "databases.*.host" yields "databases.replica.host" even when only
+// "databases.replica.port" exists, letting required rules report it.
+func ExpandPattern(pattern string, keys []string) []string {
+	pp := strings.Split(pattern, ".")
+
+	last := -1
+	for i, seg := range pp {
+		if IsPattern(seg) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return []string{pattern}
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, key := range keys {
+		kp := strings.Split(key, ".")
+		if len(kp) <= last || !matchSegments(pp[:last+1], kp[:last+1]) {
+			continue
+		}
+		concrete := strings.Join(append(append([]string(nil), kp[:last+1]...), pp[last+1:]...), ".")
+		if !seen[concrete] {
+			seen[concrete] = true
+			out = append(out, concrete)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// GCP Secret Manager Source
+// =============================================================================
+
+// SecretManagerOptions configure a Google Secret Manager source.
+type SecretManagerOptions struct {
+	Project string
+	Secrets []string // secret IDs to load; empty loads every secret in the project
+	Version string   // defaults to "latest"
+	Prefix  string   // key namespace for the secrets, e.g. "secrets"
+
+	// Subscription is an optional Pub/Sub pull subscription
+	// ("projects/<p>/subscriptions/<s>") attached to the secrets' topic.
+	// When set, Poll only refetches after a notification arrives.
+	Subscription    string
+	RefreshInterval time.Duration // minimum time between polls, defaults to 5m
+
+	Credentials GCPTokenProvider // defaults to ApplicationDefaultCredentials
+	Auth        AuthProvider     // replaces the default Credentials when set
+	Client      *http.Client
+}
+
+// SecretManagerSource loads secret versions from Google Secret Manager.
+// Secret IDs map to keys with "--" as the separator ("db--password" becomes
+// "db.password"), under Prefix when set.
+type SecretManagerSource struct {
+	BaseSource
+	opts     SecretManagerOptions
+	notifier *pubSubNotifier
+
+	mu       sync.Mutex
+	data     map[string]any
+	versions map[string]string // secret ID -> resolved version name
+	fetched  bool
+	nextPoll time.Time
+}
+
+func SecretManager(opts SecretManagerOptions) *SecretManagerSource {
+	return SecretManagerWithPriority(opts, DefaultRemotePriority)
+}
+
+func SecretManagerWithPriority(opts SecretManagerOptions, priority int) *SecretManagerSource {
+	if opts.Version == "" {
+		opts.Version = "latest"
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 5 * time.Minute
+	}
+	if opts.Credentials == nil && opts.Auth == nil {
+		opts.Credentials = ApplicationDefaultCredentials()
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(30 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+
+	s := &SecretManagerSource{
+		BaseSource: NewBaseSource("secretmanager:"+opts.Project, priority),
+		opts:       opts,
+		data:       map[string]any{},
+	}
+	if opts.Subscription != "" {
+		s.notifier = &pubSubNotifier{subscription: opts.Subscription, client: opts.Client, creds: opts.Credentials}
+	}
+	return s
+}
+
+// Remote reports that the source needs the network.
+func (s *SecretManagerSource) Remote() bool { return true }
+
+// Load returns the secrets, fetching them on first use.
+func (s *SecretManagerSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		if _, err := s.fetch(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return cloneMap(s.data), nil
+}
+
+// Poll reports whether a secret gained a new version since the last fetch.
+func (s *SecretManagerSource) Poll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if s.fetched && s.notifier != nil {
+		notified, err := s.notifier.pull(ctx)
+		if err != nil || !notified {
+			return false, err
+		}
+	} else if s.fetched && time.Now().Before(s.nextPoll) {
+		return false, nil
+	}
+	return s.fetch(ctx)
+}
+
+// SecretKeys returns the keys of the last fetch, so a Config treats them as
+// secret (see SecretKeySource).
+func (s *SecretManagerSource) SecretKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return mapKeys(s.data)
+}
+
+func (s *SecretManagerSource) fetch(ctx context.Context) (bool, error) {
+	s.nextPoll = time.Now().Add(s.opts.RefreshInterval)
+
+	ids := s.opts.Secrets
+	if len(ids) == 0 {
+		var err error
+		if ids, err = s.list(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	data := make(map[string]any, len(ids))
+	versions := make(map[string]string, len(ids))
+	for _, id := range ids {
+		version, value, err := s.access(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		versions[id] = version
+		data[joinKeys(s.opts.Prefix, strings.ReplaceAll(id, "--", "."))] = value
+	}
+
+	changed := !s.fetched || !maps.Equal(versions, s.versions)
+	s.data, s.versions = data, versions
+	s.fetched = true
+	return changed, nil
+}
+
+func (s *SecretManagerSource) list(ctx context.Context) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		q := url.Values{"pageSize": {"250"}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		endpoint := "https://secretmanager.googleapis.com/v1/projects/" + s.opts.Project + "/secrets?" + q.Encode()
+		resp, body, err := gcpRequest(ctx, s.opts.Client, s.opts.Credentials, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("secret manager: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("secret manager: list secrets: %s: %s", resp.Status, body)
+		}
+
+		var page struct {
+			Secrets []struct {
+				Name string `json:"name"`
+			} `json:"secrets"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("secret manager: decode secrets: %w", err)
+		}
+		for _, secret := range page.Secrets {
+			ids = append(ids, path.Base(secret.Name))
+		}
+		if page.NextPageToken == "" {
+			return ids, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// access returns the resolved version name and payload of a secret.
+func (s *SecretManagerSource) access(ctx context.Context, id string) (string, string, error) {
+	endpoint := fmt.Sprintf("https://secretmanager.googleapis.com/v1/projects/%s/secrets/%s/versions/%s:access",
+		s.opts.Project, url.PathEscape(id), s.opts.Version)
+	resp, body, err := gcpRequest(ctx, s.opts.Client, s.opts.Credentials, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("secret manager: secret %q: %w", id, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("secret manager: secret %q: %s", id, resp.Status)
+	}
+
+	var out struct {
+		Name    string `json:"name"`
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", "", fmt.Errorf("secret manager: decode secret %q: %w", id, err)
+	}
+	value, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", "", fmt.Errorf("secret manager: decode secret %q: %w", id, err)
+	}
+	return out.Name, string(value), nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// =============================================================================
+// Secret Keys
+// =============================================================================
+
+// RedactedValue replaces secret values in exported data.
+const RedactedValue = "***"
+
+// DefaultSecretPatterns flags keys that commonly hold credentials. Patterns
+// use path.Match syntax against the lower-cased full key, so "*" also spans
+// dots.
+var DefaultSecretPatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*apikey*",
+	"*api_key*",
+	"*private_key*",
+	"*credential*",
+}
+
+// MarkSecret flags keys matching the given patterns as secret in addition to
+// DefaultSecretPatterns. Secret values are redacted wherever configuration
+// leaves the process (notifications, exports).
+func (c *Config) MarkSecret(patterns ...string) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secretPatterns = append(c.secretPatterns, patterns...)
+	return c
+}
+
+// SecretKeySource is implemented by sources of secrets, such as KeyVault.
+// After each load, the keys a source reports are treated as secret, as if
+// marked with MarkSecret, without marking keys from other sources.
+type SecretKeySource interface {
+	SecretKeys() []string
+}
+
+// loadedSecretKeys returns the keys of data, as loaded by src, that hold
+// secrets. Wrappers and composites may rename or mix keys, so when the
+// secret source is nested every key src loaded counts.
+func loadedSecretKeys(src Source, data map[string]any) []string {
+	if sk, ok := src.(SecretKeySource); ok {
+		return sk.SecretKeys()
+	}
+	nested := false
+	walkSources(src, func(s Source) {
+		_, ok := s.(SecretKeySource)
+		nested = nested || ok
+	})
+	if !nested {
+		return nil
+	}
+	return mapKeys(data)
+}
+
+// IsSecret reports whether a key is treated as secret.
+func (c *Config) IsSecret(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isSecret(key)
+}
+
+// isSecret is IsSecret for callers already holding c.mu.
+func (c *Config) isSecret(key string) bool {
+	return c.secretKeys[key] || matchesAny(DefaultSecretPatterns, key) || matchesAny(c.secretPatterns, key)
+}
+
+// redactMap returns a copy of data with secret values replaced.
+func (c *Config) redactMap(data map[string]any) map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make(map[string]any, len(data))
+	for k, v := range data {
+		out[k] = c.redact(k, v)
+	}
+	return out
+}
+
+// redact returns v, or its redacted form when key is secret; the caller
+// must hold c.mu.
+func (c *Config) redact(key string, v any) any {
+	if !c.isSecret(key) {
+		return v
+	}
+	for _, rule := range c.redactors {
+		if matchesAny(rule.patterns, key) {
+			return rule.redactor.Redact(key, v)
+		}
+	}
+	if c.redactor != nil {
+		return c.redactor.Redact(key, v)
+	}
+	return RedactedValue
+}
+
+// =============================================================================
+// Redaction Strategies
+// =============================================================================
+
+// Redactor replaces a secret value wherever configuration leaves the
+// process.
+type Redactor interface {
+	Redact(key string, value any) any
+}
+
+// RedactorFunc adapts a function to Redactor.
+type RedactorFunc func(key string, value any) any
+
+func (f RedactorFunc) Redact(key string, value any) any { return f(key, value) }
+
+// MaskRedactor replaces every value with RedactedValue. It is the default.
+var MaskRedactor Redactor = RedactorFunc(func(string, any) any { return RedactedValue })
+
+// HMACRedactor replaces values with "hmac-sha256:<hex>", keyed by secret and
+// the config key, so operators can tell whether a secret changed between
+// exports or change sets without seeing it. Keep the HMAC key stable across
+// processes that should produce comparable output.
+func HMACRedactor(secret []byte) Redactor {
+	return RedactorFunc(func(key string, value any) any {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			raw = []byte(fmt.Sprint(value))
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(key))
+		mac.Write([]byte{0})
+		mac.Write(raw)
+		return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+	})
+}
+
+type redactionRule struct {
+	patterns []string
+	redactor Redactor
+}
+
+// RedactWith marks keys matching the patterns as secret and redacts them
+// with r. Patterns are checked in registration order; secret keys matching
+// none use the default set by WithRedactor.
+func (c *Config) RedactWith(r Redactor, patterns ...string) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secretPatterns = append(c.secretPatterns, patterns...)
+	c.redactors = append(c.redactors, redactionRule{patterns: patterns, redactor: r})
+	return c
+}
+
+// WithRedactor sets the strategy for secret keys without a RedactWith
+// pattern (default MaskRedactor).
+func WithRedactor(r Redactor) Option {
+	return func(c *Config) {
+		c.redactor = r
+	}
+}
+
+func matchesAny(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), key); ok {
+			return true
+		}
+	}
+	return false
+}
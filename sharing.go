@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Shared Loading
+// =============================================================================
+
+// SharedLoader deduplicates loads of identical sources across Config
+// instances in a process. Sources implementing SharedIdentifier, such as
+// file sources, are identical when their identities match, e.g. the same
+// file with the same prefix; any other source is only shared with itself.
+// Concurrent loads of one source share a single call, and parsed results
+// are reused while the source's watch paths are unchanged (by size and
+// modification time). Sources without watch paths are reused for the
+// loader's TTL.
+type SharedLoader struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	calls map[any]*sharedCall
+	cache map[any]sharedEntry
+}
+
+// SharedIdentifier is implemented by sources whose data is fully
+// determined by a string, so separate instances with the same identity can
+// share loads. Display names are not identities: every memory source is
+// named "memory".
+type SharedIdentifier interface {
+	SharedIdentity() string
+}
+
+// annotatedSource is a source whose loads also set annotations, such as a
+// file's _meta, which sharing must pass on to every instance.
+type annotatedSource interface {
+	Meta() FileMeta
+	setMeta(meta FileMeta)
+}
+
+type sharedCall struct {
+	done chan struct{}
+	data map[string]any
+	meta FileMeta
+	err  error
+}
+
+type sharedEntry struct {
+	data        map[string]any
+	meta        FileMeta
+	fingerprint string
+	loadedAt    time.Time
+}
+
+// DefaultSharedLoader is the process-wide loader used by Builder.WithSharedLoading.
+var DefaultSharedLoader = NewSharedLoader(0)
+
+// NewSharedLoader creates a loader. ttl bounds reuse of results from sources
+// without watch paths; zero disables caching for them (in-flight loads are
+// still shared).
+func NewSharedLoader(ttl time.Duration) *SharedLoader {
+	return &SharedLoader{
+		ttl:   ttl,
+		calls: make(map[any]*sharedCall),
+		cache: make(map[any]sharedEntry),
+	}
+}
+
+// Load returns the source's data, reusing a cached or in-flight result when
+// possible. Callers receive private copies they may modify.
+func (l *SharedLoader) Load(src Source) (map[string]any, error) {
+	key, ok := sharedKey(src)
+	if !ok {
+		return src.Load()
+	}
+	fp := fingerprint(src.WatchPaths())
+
+	annotated, _ := src.(annotatedSource)
+
+	l.mu.Lock()
+	if entry, ok := l.cache[key]; ok && l.fresh(entry, fp) {
+		l.mu.Unlock()
+		if annotated != nil {
+			annotated.setMeta(entry.meta)
+		}
+		return deepCloneMap(entry.data), nil
+	}
+	if call, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		<-call.done
+		if annotated != nil && call.err == nil {
+			annotated.setMeta(call.meta)
+		}
+		return deepCloneMap(call.data), call.err
+	}
+	call := &sharedCall{done: make(chan struct{})}
+	l.calls[key] = call
+	l.mu.Unlock()
+
+	call.data, call.err = src.Load()
+	if annotated != nil {
+		call.meta = annotated.Meta()
+	}
+
+	l.mu.Lock()
+	delete(l.calls, key)
+	if call.err == nil && (fp != "" || l.ttl > 0) {
+		l.cache[key] = sharedEntry{data: call.data, meta: call.meta, fingerprint: fp, loadedAt: time.Now()}
+	}
+	l.mu.Unlock()
+	close(call.done)
+
+	return deepCloneMap(call.data), call.err
+}
+
+// Forget drops the cached result for a source.
+func (l *SharedLoader) Forget(src Source) {
+	key, ok := sharedKey(src)
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cache, key)
+}
+
+// sharedKey returns the cache key of a source: its SharedIdentity, or the
+// source itself. Sources that are neither are not shared.
+func sharedKey(src Source) (any, bool) {
+	if id, ok := src.(SharedIdentifier); ok {
+		if key := id.SharedIdentity(); key != "" {
+			return key, true
+		}
+	}
+	if reflect.TypeOf(src).Comparable() {
+		return src, true
+	}
+	return nil, false
+}
+
+func (l *SharedLoader) fresh(entry sharedEntry, fp string) bool {
+	if fp != "" {
+		return entry.fingerprint == fp
+	}
+	return l.ttl > 0 && time.Since(entry.loadedAt) < l.ttl
+}
+
+// fingerprint summarises the size and modification time of paths.
+func fingerprint(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&sb, "%s:%d:%d;", p, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&sb, "%s:missing;", p)
+		}
+	}
+	return sb.String()
+}
+
+// SharedSource routes loads of a source through a SharedLoader.
+type SharedSource struct {
+	BaseSource
+	source Source
+	loader *SharedLoader
+}
+
+// NewSharedSource wraps a source so identical instances share loads.
+func NewSharedSource(source Source, loader *SharedLoader) *SharedSource {
+	return &SharedSource{
+		BaseSource: NewBaseSource("shared:"+source.Name(), source.Priority()),
+		source:     source,
+		loader:     loader,
+	}
+}
+
+func (s *SharedSource) Load() (map[string]any, error) {
+	return s.loader.Load(s.source)
+}
+
+// Unwrap returns the wrapped source.
+func (s *SharedSource) Unwrap() Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *SharedSource) Priority() int { return s.source.Priority() }
+
+func (s *SharedSource) WatchPaths() []string {
+	return s.source.WatchPaths()
+}
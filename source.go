@@ -1,55 +1,56 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
-	"gopkg.in/yaml.v3"
+	"github.com/os-golib/go-config/source"
 )
 
 // =============================================================================
-// Core Interfaces
+// Core Interfaces (re-exported from the source package)
 // =============================================================================
 
 // Source is a typed configuration provider.
 // Higher priority overrides lower priority.
-type Source interface {
-	Name() string
-	Priority() int
-	Load() (map[string]any, error)
-	WatchPaths() []string
+type Source = source.Source
+
+// Poller is implemented by sources that detect remote changes themselves.
+type Poller = source.Poller
+
+// Unwrapper is implemented by middleware sources wrapping a single source.
+type Unwrapper = source.Unwrapper
+
+// walkSources calls fn for src and every source nested inside it.
+func walkSources(src Source, fn func(Source)) {
+	source.Walk(src, fn)
 }
 
 // =============================================================================
 // Base Source
 // =============================================================================
 
-type BaseSource struct {
-	name     string
-	priority int
-	paths    []string
-}
+type BaseSource = source.Base
 
 func NewBaseSource(name string, priority int, paths ...string) BaseSource {
-	return BaseSource{name: name, priority: priority, paths: paths}
+	return source.NewBase(name, priority, paths...)
 }
 
-func (b BaseSource) Name() string         { return b.name }
-func (b BaseSource) Priority() int        { return b.priority }
-func (b BaseSource) WatchPaths() []string { return b.paths }
-
 // =============================================================================
-// Default Priorities (single source of truth)
+// Default Priorities
 // =============================================================================
 
 const (
-	DefaultMemoryPriority = 0
-	DefaultFilePriority   = 10
-	DefaultGlobPriority   = 10
-	DefaultEnvPriority    = 20
+	DefaultMemoryPriority = source.DefaultMemoryPriority
+	DefaultFilePriority   = source.DefaultFilePriority
+	DefaultGlobPriority   = source.DefaultGlobPriority
+	DefaultRemotePriority = source.DefaultRemotePriority
+	DefaultEnvPriority    = source.DefaultEnvPriority
+	DefaultKVPriority     = source.DefaultKVPriority
 )
 
 // =============================================================================
@@ -77,6 +78,12 @@ var sourceRegistry = map[string]SourceBuilder{
 	"env": func(a SourceArgs, p int) Source {
 		return EnvWithPriority(a.Prefix, p)
 	},
+	"object": func(a SourceArgs, p int) Source {
+		return ObjectWithPriority(a.Path, ObjectOptions{}, p)
+	},
+	"exec": func(a SourceArgs, p int) Source {
+		return ExecWithPriority(a.Path, ExecOptions{}, p)
+	},
 }
 
 // CreateSource is the ONLY source factory entry point.
@@ -91,12 +98,24 @@ func autoDetectSource(args SourceArgs, priority int) Source {
 	if args.Path == "" {
 		return MemoryWithPriority(args.Data, priority)
 	}
+	if isObjectURL(args.Path) {
+		return ObjectWithPriority(args.Path, ObjectOptions{}, priority)
+	}
 	if isGlob(args.Path) {
 		return GlobWithPriority(args.Path, priority)
 	}
 	return FileWithPriority(args.Path, priority)
 }
 
+func isObjectURL(path string) bool {
+	for _, scheme := range []string{"s3://", "gs://", "azblob://"} {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
 func isGlob(path string) bool {
 	for _, r := range path {
 		switch r {
@@ -142,7 +161,12 @@ func (s *MemorySource) Update(data map[string]any) {
 type FileSource struct {
 	BaseSource
 	path    string
+	prefix  string
 	decoder FileDecoder
+
+	mu    sync.Mutex
+	fixed bool // priority set explicitly, ignoring annotations
+	meta  FileMeta
 }
 
 func File(path string) *FileSource {
@@ -167,8 +191,122 @@ func (s *FileSource) Load() (map[string]any, error) {
 	if err := s.decoder.Decode(raw, &decoded); err != nil {
 		return nil, fmt.Errorf("decode file: %w", err)
 	}
+	meta, err := parseFileMeta(decoded[MetaKey])
+	if err != nil {
+		return nil, fmt.Errorf("decode file: %w", err)
+	}
+	delete(decoded, MetaKey)
+	s.setMeta(meta)
 
-	return flattenToDot(decoded), nil
+	out := flattenToDot(decoded)
+	prefix := s.prefix
+	if prefix == "" {
+		prefix = meta.Namespace
+	}
+	if prefix == "" {
+		return out, nil
+	}
+	prefixed := make(map[string]any, len(out))
+	for k, v := range out {
+		prefixed[prefix+"."+k] = v
+	}
+	return prefixed, nil
+}
+
+// SharedIdentity identifies the file's data for shared loading: its path
+// and prefix.
+func (s *FileSource) SharedIdentity() string {
+	return "file:" + s.path + "\x00" + s.prefix
+}
+
+// WithPrefix nests the file's keys under prefix, e.g. a countries.csv
+// table under "countries". It overrides a _meta.namespace annotation.
+func (s *FileSource) WithPrefix(prefix string) *FileSource {
+	s.prefix = strings.Trim(prefix, ".")
+	return s
+}
+
+// WithPriority sets the file's priority, overriding a _meta.priority
+// annotation.
+func (s *FileSource) WithPriority(priority int) *FileSource {
+	s.BaseSource = NewBaseSource(s.Name(), priority, s.WatchPaths()...)
+	s.fixed = true
+	return s
+}
+
+// Priority returns the priority annotated in the file as of the last
+// load, unless one was set with WithPriority.
+func (s *FileSource) Priority() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.meta.Priority != nil && !s.fixed {
+		return *s.meta.Priority
+	}
+	return s.BaseSource.Priority()
+}
+
+// Meta returns the annotations read by the last load.
+func (s *FileSource) Meta() FileMeta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.meta
+}
+
+func (s *FileSource) setMeta(meta FileMeta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.meta = meta
+}
+
+// =============================================================================
+// File Annotations
+// =============================================================================
+
+// MetaKey is the top-level key under which a file annotates itself:
+//
+//	_meta:
+//	  priority: 40         # load order among sources, higher wins
+//	  namespace: payments  # nest the file's keys under payments.
+//
+// Annotations suit drop-in conf.d fragments owned by different teams. The
+// builder's choices win: WithPriority and WithPrefix override them. The
+// _meta key itself is not loaded.
+const MetaKey = "_meta"
+
+// FileMeta holds a file's annotations.
+type FileMeta struct {
+	Priority  *int   // nil when not annotated
+	Namespace string // empty when not annotated
+}
+
+func parseFileMeta(v any) (FileMeta, error) {
+	var meta FileMeta
+	if v == nil {
+		return meta, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return meta, fmt.Errorf("%s must be a map, got %T", MetaKey, v)
+	}
+	for k, val := range m {
+		switch k {
+		case "priority":
+			p, ok := val.(int)
+			if !ok {
+				return meta, fmt.Errorf("%s.priority must be an integer, got %v", MetaKey, val)
+			}
+			meta.Priority = &p
+		case "namespace":
+			ns, ok := val.(string)
+			if !ok {
+				return meta, fmt.Errorf("%s.namespace must be a string, got %v", MetaKey, val)
+			}
+			meta.Namespace = strings.Trim(ns, ".")
+		default:
+			return meta, fmt.Errorf("unknown annotation %s.%s", MetaKey, k)
+		}
+	}
+	return meta, nil
 }
 
 // =============================================================================
@@ -183,10 +321,10 @@ type FileDecoder interface {
 type jsonDecoder struct{}
 type yamlDecoder struct{}
 
-func (jsonDecoder) Decode(b []byte, v any) error { return json.Unmarshal(b, v) }
+func (jsonDecoder) Decode(b []byte, v any) error { return decodeJSON(b, v) }
 func (jsonDecoder) Extensions() []string         { return []string{".json"} }
 
-func (yamlDecoder) Decode(b []byte, v any) error { return yaml.Unmarshal(b, v) }
+func (yamlDecoder) Decode(b []byte, v any) error { return decodeYAML(b, v) }
 func (yamlDecoder) Extensions() []string {
 	return []string{".yaml", ".yml"}
 }
@@ -194,6 +332,8 @@ func (yamlDecoder) Extensions() []string {
 var decoders = []FileDecoder{
 	jsonDecoder{},
 	yamlDecoder{},
+	CSVDecoder{extensions: []string{".csv"}},
+	CSVDecoder{Comma: '\t', extensions: []string{".tsv", ".tab"}},
 }
 
 func RegisterDecoder(d FileDecoder) {
@@ -232,19 +372,38 @@ func GlobWithPriority(pattern string, priority int) *MultiFileSource {
 	}
 }
 
+// WatchTargets reports the pattern, so files matching it later are picked up.
+func (s *MultiFileSource) WatchTargets() []SourceTarget {
+	return []SourceTarget{{Kind: WatchTargetGlob, Path: s.pattern}}
+}
+
 func (s *MultiFileSource) Load() (map[string]any, error) {
 	files, err := filepath.Glob(s.pattern)
 	if err != nil {
 		return nil, fmt.Errorf("glob pattern: %w", err)
 	}
 
-	out := make(map[string]any)
+	// Files merge in name order, or by their _meta.priority annotations.
+	type fragment struct {
+		priority int
+		data     map[string]any
+	}
+	fragments := make([]fragment, 0, len(files))
 	for _, f := range files {
-		data, err := FileWithPriority(f, s.Priority()).Load()
+		fs := FileWithPriority(f, 0)
+		data, err := fs.Load()
 		if err != nil {
 			return nil, fmt.Errorf("load %s: %w", f, err)
 		}
-		for k, v := range data {
+		fragments = append(fragments, fragment{fs.Priority(), data})
+	}
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return fragments[i].priority < fragments[j].priority
+	})
+
+	out := make(map[string]any)
+	for _, fr := range fragments {
+		for k, v := range fr.data {
 			out[k] = v
 		}
 	}
@@ -309,6 +468,12 @@ func (s *EnvSource) Load() (map[string]any, error) {
 // Flattening (single unified logic)
 // =============================================================================
 
+// Flatten returns a copy of data with nested maps and lists flattened to
+// dot keys, the way file sources load them.
+func Flatten(data map[string]any) map[string]any {
+	return flattenToDot(data)
+}
+
 func flattenToDot(in map[string]any) map[string]any {
 	out := make(map[string]any)
 	flatten("", in, out)
@@ -377,6 +542,34 @@ func cloneMap(m map[string]any) map[string]any {
 	return out
 }
 
+// deepCloneMap copies a map including nested maps and slices, so callers
+// that merge into the result cannot mutate shared data.
+func deepCloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = deepCloneValue(v)
+	}
+	return out
+}
+
+func deepCloneValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		return deepCloneMap(x)
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = deepCloneValue(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
 // KeyTransforms provides common key transformation functions.
 var KeyTransforms = struct {
 	Lower           KeyTransformer
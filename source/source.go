@@ -0,0 +1,132 @@
+// Package source defines the interfaces configuration sources and
+// middleware implement. It has no dependencies outside the standard
+// library, so third-party sources can build against it alone; the root
+// config package re-exports everything here under its historical names.
+package source
+
+// =============================================================================
+// Core Interfaces
+// =============================================================================
+
+// Source is a typed configuration provider.
+// Higher priority overrides lower priority.
+type Source interface {
+	Name() string
+	Priority() int
+	Load() (map[string]any, error)
+	WatchPaths() []string
+}
+
+// Poller is implemented by sources that detect remote changes themselves
+// (e.g. by polling an API). Watch calls Poll on every tick; returning true
+// triggers a reload, after which Load returns the new data.
+type Poller interface {
+	Poll() (changed bool, err error)
+}
+
+// Unwrapper is implemented by middleware sources wrapping a single source.
+type Unwrapper interface {
+	Unwrap() Source
+}
+
+// Watch target kinds.
+const (
+	TargetFile = "file" // a single file, compared by modification time
+	TargetGlob = "glob" // a glob pattern; new and modified matches count
+	TargetPoll = "poll" // a Poller
+)
+
+// Target is one thing a source can be watched through.
+type Target struct {
+	Kind string
+	Path string // file path or glob pattern; empty for TargetPoll
+}
+
+// Targeter is implemented by sources that describe their watch targets
+// directly. It takes precedence over WatchPaths, which only expresses
+// files.
+type Targeter interface {
+	WatchTargets() []Target
+}
+
+// Targets returns the watch targets src itself contributes: its Targeter
+// targets, or one TargetFile per watch path, plus TargetPoll for a Poller.
+// Wrappers and composites contribute nothing of their own; their nested
+// sources are visited by Walk.
+func Targets(src Source) []Target {
+	var out []Target
+	_, wraps := src.(Unwrapper)
+	_, composes := src.(interface{ Sources() []Source })
+	if !wraps && !composes {
+		if t, ok := src.(Targeter); ok {
+			out = append(out, t.WatchTargets()...)
+		} else {
+			for _, p := range src.WatchPaths() {
+				out = append(out, Target{Kind: TargetFile, Path: p})
+			}
+		}
+	}
+	if _, ok := src.(Poller); ok {
+		out = append(out, Target{Kind: TargetPoll})
+	}
+	return out
+}
+
+// Middleware wraps a source with additional behavior.
+type Middleware func(Source) Source
+
+// Chain composes middleware; the first one wraps outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return func(src Source) Source {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			src = middleware[i](src)
+		}
+		return src
+	}
+}
+
+// Walk calls fn for src and every source nested inside it, through
+// middleware wrappers and composite children.
+func Walk(src Source, fn func(Source)) {
+	fn(src)
+	switch s := src.(type) {
+	case Unwrapper:
+		Walk(s.Unwrap(), fn)
+	case interface{ Sources() []Source }:
+		for _, child := range s.Sources() {
+			Walk(child, fn)
+		}
+	}
+}
+
+// =============================================================================
+// Base Source
+// =============================================================================
+
+// Base implements Name, Priority and WatchPaths for embedding.
+type Base struct {
+	name     string
+	priority int
+	paths    []string
+}
+
+func NewBase(name string, priority int, paths ...string) Base {
+	return Base{name: name, priority: priority, paths: paths}
+}
+
+func (b Base) Name() string         { return b.name }
+func (b Base) Priority() int        { return b.priority }
+func (b Base) WatchPaths() []string { return b.paths }
+
+// =============================================================================
+// Priorities
+// =============================================================================
+
+const (
+	DefaultMemoryPriority = 0
+	DefaultFilePriority   = 10
+	DefaultGlobPriority   = 10
+	DefaultRemotePriority = 15
+	DefaultEnvPriority    = 20
+	DefaultKVPriority     = 30
+)
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// =============================================================================
+// Starlark Source
+// =============================================================================
+
+// ScriptLimits bound a single script execution. Timeout is enforced by the
+// source; MaxSteps is passed to the runtime.
+type ScriptLimits struct {
+	MaxSteps uint64        // execution steps, defaults to 1,000,000
+	Timeout  time.Duration // defaults to 1s
+}
+
+// ScriptRuntime executes a Starlark script with the given predeclared
+// globals and returns its global variables converted to Go values (dicts
+// as map[string]any, lists as []any). Adapt your interpreter (for example
+// go.starlark.net) to this interface; it must not offer load() or any
+// module with host access, must stop after limits.MaxSteps, and should
+// cancel the thread when ctx is done. Until a run that ignored the timeout
+// returns, the source fails loads with ErrStillRunning.
+type ScriptRuntime interface {
+	Run(ctx context.Context, filename string, src []byte, globals map[string]any, limits ScriptLimits) (map[string]any, error)
+}
+
+// ScriptRuntimeFunc adapts a function to ScriptRuntime.
+type ScriptRuntimeFunc func(ctx context.Context, filename string, src []byte, globals map[string]any, limits ScriptLimits) (map[string]any, error)
+
+func (f ScriptRuntimeFunc) Run(ctx context.Context, filename string, src []byte, globals map[string]any, limits ScriptLimits) (map[string]any, error) {
+	return f(ctx, filename, src, globals, limits)
+}
+
+// StarlarkOptions configure a Starlark source.
+type StarlarkOptions struct {
+	Env      []string          // environment variables exposed in env; others are invisible
+	Profile  string            // exposed as profile
+	Metadata map[string]string // exposed as metadata, e.g. region or version
+	Limits   ScriptLimits
+}
+
+// StarlarkSource produces configuration by running a sandboxed Starlark
+// script, for programmable config without recompiling. The script sees
+// only three predeclared globals: env (a dict of the allowed variables that
+// are set), profile and metadata, and must assign a dict to the global
+// config. Nested dicts are flattened to dot keys.
+//
+//	config = {
+//	    "server": {"port": 8080 if profile == "prod" else 3000},
+//	    "region": metadata["region"],
+//	}
+type StarlarkSource struct {
+	BaseSource
+	path    string
+	runtime ScriptRuntime
+	opts    StarlarkOptions
+	calls   boundedCalls
+}
+
+func Starlark(path string, runtime ScriptRuntime, opts StarlarkOptions) *StarlarkSource {
+	return StarlarkWithPriority(path, runtime, opts, DefaultFilePriority)
+}
+
+func StarlarkWithPriority(path string, runtime ScriptRuntime, opts StarlarkOptions, priority int) *StarlarkSource {
+	if opts.Limits.MaxSteps == 0 {
+		opts.Limits.MaxSteps = 1_000_000
+	}
+	if opts.Limits.Timeout <= 0 {
+		opts.Limits.Timeout = time.Second
+	}
+	return &StarlarkSource{
+		BaseSource: NewBaseSource("starlark:"+path, priority, path),
+		path:       path,
+		runtime:    runtime,
+		opts:       opts,
+	}
+}
+
+func (s *StarlarkSource) Load() (map[string]any, error) {
+	src, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read script: %w", err)
+	}
+
+	globals, err := s.run(src)
+	if err != nil {
+		return nil, fmt.Errorf("starlark %s: %w", s.path, err)
+	}
+	cfg, ok := globals["config"]
+	if !ok {
+		return nil, fmt.Errorf("starlark %s: script does not define config", s.path)
+	}
+	m, ok := cfg.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("starlark %s: config is %T, not a dict", s.path, cfg)
+	}
+	return flattenToDot(m), nil
+}
+
+// globals returns the script's predeclared values.
+func (s *StarlarkSource) globals() map[string]any {
+	env := make(map[string]any, len(s.opts.Env))
+	for _, name := range s.opts.Env {
+		if v, ok := os.LookupEnv(name); ok {
+			env[name] = v
+		}
+	}
+	metadata := make(map[string]any, len(s.opts.Metadata))
+	for k, v := range s.opts.Metadata {
+		metadata[k] = v
+	}
+	return map[string]any{
+		"env":      env,
+		"profile":  s.opts.Profile,
+		"metadata": metadata,
+	}
+}
+
+// run executes the script, returning once the timeout expires even if the
+// runtime does not honour cancellation.
+func (s *StarlarkSource) run(src []byte) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Limits.Timeout)
+	defer cancel()
+	return runBounded(&s.calls, ctx, func(ctx context.Context) (map[string]any, error) {
+		return s.runtime.Run(ctx, s.path, src, s.globals(), s.opts.Limits)
+	})
+}
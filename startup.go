@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// =============================================================================
+// Startup Retry
+// =============================================================================
+
+// RetryPolicy controls how LoadWithRetry retries the initial load, e.g.
+// while a config service is still starting during a rolling deploy.
+type RetryPolicy struct {
+	GracePeriod    time.Duration // give up after this long; zero waits until ctx is done
+	InitialBackoff time.Duration // delay after the first failure (default 500ms)
+	MaxBackoff     time.Duration // cap on the delay (default 30s)
+	Multiplier     float64       // delay growth per attempt (default 2)
+	Jitter         float64       // random fraction added to each delay, 0 to 1
+
+	// Retryable reports whether a failed load is worth retrying; by
+	// default every error is.
+	Retryable func(error) bool
+	// OnRetry is called before waiting to retry.
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// LoadWithRetry loads the configuration, retrying the whole load with
+// backoff until it succeeds, the grace period elapses, ctx is done or an
+// error is not retryable. It returns the last load error in the latter
+// cases.
+func (c *Config) LoadWithRetry(ctx context.Context, policy RetryPolicy) error {
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = 500 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 30 * time.Second
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 2
+	}
+	if policy.GracePeriod > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.GracePeriod)
+		defer cancel()
+	}
+
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.Load()
+		if err == nil {
+			return nil
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return err
+		}
+
+		wait := backoff
+		if policy.Jitter > 0 {
+			wait += time.Duration(rand.Float64() * policy.Jitter * float64(wait))
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return fmt.Errorf("config: load failed after %d attempts: %w", attempt, err)
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("config: load failed after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+		backoff = min(time.Duration(float64(backoff)*policy.Multiplier), policy.MaxBackoff)
+	}
+}
@@ -0,0 +1,73 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+)
+
+// =============================================================================
+// Stable Values
+// =============================================================================
+
+// The functions below derive pseudo-random values from a seed key, such as
+// a hostname, so a templated value like a shard number or a jittered
+// schedule is the same on every restart and on every machine. They depend
+// only on the key and are stable across releases.
+
+// StableUint64 returns a uniformly distributed value derived from key.
+func StableUint64(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// StableInt returns a value in [0, n) derived from key. It returns 0 if
+// n <= 0.
+func StableInt(key string, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(StableUint64(key) % uint64(n))
+}
+
+// StableFloat returns a value in [0, 1) derived from key.
+func StableFloat(key string) float64 {
+	return float64(StableUint64(key)>>11) / (1 << 53)
+}
+
+// StablePick returns one of choices derived from key, or "" if there are
+// none.
+func StablePick(key string, choices ...string) string {
+	if len(choices) == 0 {
+		return ""
+	}
+	return choices[StableInt(key, len(choices))]
+}
+
+// Shard assigns key to one of n shards using jump consistent hashing: when
+// n grows, only about 1/n of the keys move to a new shard, and none move
+// between existing ones. It returns 0 if n <= 0.
+func Shard(key string, n int) int {
+	h := StableUint64(key)
+	b, j := int64(-1), int64(0)
+	for j < int64(n) {
+		b = j
+		h = h*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((h>>33)+1)))
+	}
+	return int(max(b, 0))
+}
+
+// stableFuncs are the template functions for stable values:
+//
+//	{{ shard hostname 16 }}
+//	{{ stableInt (print hostname "/backup") 60 }}
+//	{{ stablePick hostname "us-east-1a" "us-east-1b" }}
+var stableFuncs = map[string]any{
+	"hostname":    os.Hostname,
+	"stableHash":  StableUint64,
+	"stableInt":   StableInt,
+	"stableFloat": StableFloat,
+	"stablePick":  StablePick,
+	"shard":       Shard,
+}
@@ -0,0 +1,183 @@
+// Package template expands Go text/template expressions in configuration
+// values.
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/os-golib/go-config/source"
+)
+
+// Processor processes configuration values using Go templates.
+type Processor struct {
+	funcMap texttemplate.FuncMap
+}
+
+// NewProcessor creates a new Processor with default functions.
+func NewProcessor() *Processor {
+	tp := &Processor{
+		funcMap: texttemplate.FuncMap{
+			"env":        os.Getenv,
+			"lower":      strings.ToLower,
+			"upper":      strings.ToUpper,
+			"split":      strings.Split,
+			"join":       strings.Join,
+			"replace":    strings.ReplaceAll,
+			"contains":   strings.Contains,
+			"hasPrefix":  strings.HasPrefix,
+			"hasSuffix":  strings.HasSuffix,
+			"trim":       strings.TrimSpace,
+			"trimSpace":  strings.TrimSpace,
+			"trimPrefix": strings.TrimPrefix,
+			"trimSuffix": strings.TrimSuffix,
+			"repeat":     strings.Repeat,
+			"toUpper":    strings.ToUpper,
+			"toLower":    strings.ToLower,
+			// "title":      strings.Title,
+			"eq":         reflect.DeepEqual,
+			"ne":         reflect.DeepEqual,
+			"lt":         func(a, b int) bool { return a < b },
+			"le":         func(a, b int) bool { return a <= b },
+			"gt":         func(a, b int) bool { return a > b },
+			"ge":         func(a, b int) bool { return a >= b },
+			"and":        func(a, b bool) bool { return a && b },
+			"or":         func(a, b bool) bool { return a || b },
+			"not":        func(a bool) bool { return !a },
+			"type":       func(v any) string { return reflect.TypeOf(v).String() },
+			"len":        func(v any) int { return reflect.ValueOf(v).Len() },
+			"format":     fmt.Sprintf,
+			"formatBool": func(b bool) string { return fmt.Sprintf("%t", b) },
+			"formatUint": func(u uint64) string { return fmt.Sprintf("%d", u) },
+			"formatInt":  func(i int) string { return fmt.Sprintf("%d", i) },
+			"formatFloat": func(f float64, precision int) string {
+				return fmt.Sprintf(fmt.Sprintf("%%.%df", precision), f)
+			},
+			"default": func(def, val string) string {
+				if val == "" {
+					return def
+				}
+				return val
+			},
+		},
+	}
+	for name, fn := range stableFuncs {
+		tp.funcMap[name] = fn
+	}
+	return tp
+}
+
+// Clone returns a processor with a copy of the function map.
+func (tp *Processor) Clone() *Processor {
+	funcs := make(texttemplate.FuncMap, len(tp.funcMap))
+	for k, v := range tp.funcMap {
+		funcs[k] = v
+	}
+	return &Processor{funcMap: funcs}
+}
+
+// AddFunction adds a custom function to the template processor's function map.
+func (tp *Processor) AddFunction(name string, fn interface{}) {
+	tp.funcMap[name] = fn
+}
+
+// Process recursively processes a configuration map, executing any templates found in string values.
+func (tp *Processor) Process(data map[string]any) (map[string]any, error) {
+	result := make(map[string]any)
+	for key, value := range data {
+		processed, err := tp.processValue(value, data)
+		if err != nil {
+			return nil, fmt.Errorf("processing key %q: %w", key, err)
+		}
+		result[key] = processed
+	}
+	return result, nil
+}
+
+// processValue recursively processes a value, handling maps, slices, and strings.
+func (tp *Processor) processValue(value any, ctx map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "{{") && strings.Contains(v, "}}") {
+			tmpl, err := texttemplate.New("config").
+				Funcs(tp.funcMap).
+				Option("missingkey=error").
+				Parse(v)
+			if err != nil {
+				return nil, err
+			}
+
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, ctx); err != nil {
+				return nil, err
+			}
+			return buf.String(), nil
+		}
+		return v, nil
+
+	case map[string]any:
+		out := make(map[string]any)
+		for k, val := range v {
+			p, err := tp.processValue(val, ctx)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = p
+		}
+		return out, nil
+
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			p, err := tp.processValue(val, ctx)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = p
+		}
+		return out, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// Source is a wrapper that applies template processing to another source.
+type Source struct {
+	source.Base
+	source    source.Source
+	processor *Processor
+}
+
+// NewSource creates a new Source.
+func NewSource(src source.Source, processor *Processor) *Source {
+	return &Source{
+		Base:      source.NewBase("template:"+src.Name(), src.Priority()),
+		source:    src,
+		processor: processor,
+	}
+}
+
+// Load loads data from the underlying source and processes it with templates.
+func (s *Source) Load() (map[string]any, error) {
+	data, err := s.source.Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.processor.Process(data)
+}
+
+// Unwrap returns the wrapped source.
+func (s *Source) Unwrap() source.Source { return s.source }
+
+// Priority returns the wrapped source's priority.
+func (s *Source) Priority() int { return s.source.Priority() }
+
+// WatchPaths returns the watch paths from the underlying source.
+func (s *Source) WatchPaths() []string {
+	return s.source.WatchPaths()
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Environment Overrides (12-factor)
+// =============================================================================
+
+// EnvOverride tells whether a key can be overridden from the environment.
+type EnvOverride struct {
+	Key     string
+	Source  string // source supplying the key, empty when unset
+	Var     string // variable that overrides the key, e.g. "APP_DB_HOST"
+	Problem string // why the key cannot be overridden, empty when it can
+}
+
+// OK reports whether the key can be overridden.
+func (o EnvOverride) OK() bool { return o.Problem == "" }
+
+// WithTwelveFactor makes Load fail unless every loaded key, and every key
+// with a validation rule, can be overridden by an environment variable
+// through the configured env sources, their prefixes and key transforms.
+func WithTwelveFactor() Option {
+	return func(c *Config) {
+		c.twelveFactor = true
+	}
+}
+
+// EnvOverrides reports, for each key, the environment variable overriding
+// it or why there is none: no env source maps a variable name to the key
+// (e.g. "db.max_conns" under the default underscore-to-dot transform), or
+// the key comes from a source loaded after every env source that does.
+// Without keys, all loaded keys and rule keys are checked.
+func (c *Config) EnvOverrides(keys ...string) []EnvOverride {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.envOverrides(keys, c.data, c.provenance, c.suppliers)
+}
+
+// CheckEnvOverrides returns an error listing the keys EnvOverrides reports
+// as not overridable.
+func (c *Config) CheckEnvOverrides(keys ...string) error {
+	return envOverrideError(c.EnvOverrides(keys...))
+}
+
+// envOverrides implements EnvOverrides for data loaded with provenance and
+// the top-level sources that supplied each key; the caller must hold c.mu.
+func (c *Config) envOverrides(keys []string, data map[string]any, provenance map[string]string, suppliers map[string]Source) []EnvOverride {
+	if len(keys) == 0 {
+		seen := make(map[string]any)
+		for k := range data {
+			if !hasIndexSegment(k) {
+				seen[k] = true
+			}
+		}
+		for k := range c.validationRules {
+			if !isKeyPattern(k) {
+				seen[k] = true
+			}
+		}
+		keys = mapKeys(seen)
+	}
+	sort.Strings(keys)
+
+	type envAt struct {
+		src   *EnvSource
+		index int // position of the top-level source in load order
+	}
+	var envs []envAt
+	for i, src := range c.sources {
+		walkSources(src, func(s Source) {
+			if e, ok := s.(*EnvSource); ok {
+				envs = append(envs, envAt{e, i})
+			}
+		})
+	}
+
+	out := make([]EnvOverride, len(keys))
+	for i, k := range keys {
+		o := EnvOverride{Key: k, Source: provenance[k]}
+		supplier, fromSource := c.sourceIndex(suppliers[k])
+		shadowed := ""
+		for _, e := range envs {
+			name, ok := e.src.varFor(k)
+			if !ok {
+				continue
+			}
+			if fromSource && e.index < supplier {
+				shadowed = name
+				continue
+			}
+			o.Var = name
+			break
+		}
+		switch {
+		case o.Var != "":
+		case len(envs) == 0:
+			o.Problem = "no env source"
+		case shadowed != "":
+			o.Problem = fmt.Sprintf("%s outranks %s", o.Source, shadowed)
+		default:
+			o.Problem = "no variable name maps to this key"
+		}
+		out[i] = o
+	}
+	return out
+}
+
+// sourceIndex returns the position of src in load order. Sources are
+// matched by identity, not name, since names need not be unique.
+func (c *Config) sourceIndex(src Source) (int, bool) {
+	if src == nil {
+		return 0, false
+	}
+	for i, s := range c.sources {
+		if sameSource(s, src) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func envOverrideError(overrides []EnvOverride) error {
+	var lines []string
+	for _, o := range overrides {
+		if !o.OK() {
+			lines = append(lines, fmt.Sprintf("%s: %s", o.Key, o.Problem))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return errors.New("keys not overridable from the environment:\n  " + strings.Join(lines, "\n  "))
+}
+
+// varFor returns a variable name this source maps to key. The transform
+// cannot be inverted in general, so likely spellings are tried and
+// checked by applying it.
+func (s *EnvSource) varFor(key string) (string, bool) {
+	underscored := strings.ReplaceAll(key, ".", "_")
+	for _, name := range []string{
+		strings.ToUpper(underscored),
+		envName(key),
+		underscored,
+		key,
+		strings.ToUpper(key),
+	} {
+		got := name
+		if s.transform != nil {
+			got = s.transform(name)
+		}
+		if got == key {
+			return s.prefix + name, true
+		}
+	}
+	return "", false
+}
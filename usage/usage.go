@@ -0,0 +1,226 @@
+// Package usage finds the configuration keys a codebase reads and checks
+// them against the keys and rules a configuration declares, so CI can fail
+// on typo'd key strings. It is built on golang.org/x/tools/go/packages and
+// kept out of the root package so only tooling pays for that dependency.
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	config "github.com/os-golib/go-config"
+	"github.com/os-golib/go-config/rules"
+	"golang.org/x/tools/go/packages"
+)
+
+// =============================================================================
+// Analysis
+// =============================================================================
+
+// configPath is the import path whose Config methods are tracked.
+const configPath = "github.com/os-golib/go-config"
+
+// Methods are the Config methods whose first argument is a key.
+var Methods = []string{
+	"Get", "MustGet", "Lookup", "IsNull",
+	"GetString", "GetInt", "GetBool", "GetDuration", "GetFloat", "GetStringSlice",
+	"GetStringE", "GetIntE", "GetBoolE", "GetDurationE", "GetFloatE", "GetStringSliceE",
+}
+
+// Options configure Analyze.
+type Options struct {
+	Dir      string   // directory the patterns are resolved in, defaults to "."
+	Patterns []string // package patterns, defaults to "./..."
+	Tests    bool     // include test files
+
+	// Declared keys. Config contributes its loaded keys and rule keys;
+	// Keys adds more, e.g. from config.ScanKeys. Keys may be patterns
+	// such as "servers.*.port".
+	Config *config.Config
+	Keys   []string
+}
+
+// Use is one read of a key.
+type Use struct {
+	Key    string // empty when the key is not a constant expression
+	Method string
+	Pos    token.Position
+}
+
+func (u Use) String() string {
+	return fmt.Sprintf("%s: %s(%q)", u.Pos, u.Method, u.Key)
+}
+
+// Report lists the key reads found by Analyze.
+type Report struct {
+	Uses    []Use // reads with a constant key
+	Unknown []Use // reads of keys that are not declared
+	Dynamic []Use // reads whose key is computed at run time
+}
+
+// Err returns an error listing unknown keys, or nil when there are none.
+func (r *Report) Err() error {
+	if len(r.Unknown) == 0 {
+		return nil
+	}
+	lines := make([]string, len(r.Unknown))
+	for i, u := range r.Unknown {
+		lines[i] = fmt.Sprintf("%s: unknown config key %q", u.Pos, u.Key)
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// Analyze loads the packages matching opts.Patterns with type information
+// and reports every call of a Methods method on *config.Config (or a type
+// embedding it). Key arguments may be string literals or constants, such
+// as those produced by config.GenerateKeys.
+func Analyze(opts Options) (*Report, error) {
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = []string{"./..."}
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:   opts.Dir,
+		Tests: opts.Tests,
+	}, opts.Patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, e := range p.Errors {
+			errs = append(errs, e)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	declared := declaredKeys(opts)
+	methods := make(map[string]bool, len(Methods))
+	for _, m := range Methods {
+		methods[m] = true
+	}
+
+	report := &Report{}
+	seen := make(map[token.Position]bool)
+	for _, p := range pkgs {
+		for _, file := range p.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				method, ok := configMethod(p.TypesInfo, call)
+				if !ok || !methods[method] {
+					return true
+				}
+				use := Use{Method: method, Pos: p.Fset.Position(call.Pos())}
+				if seen[use.Pos] {
+					return true // test variants repeat their package's files
+				}
+				seen[use.Pos] = true
+
+				tv := p.TypesInfo.Types[call.Args[0]]
+				if tv.Value == nil || tv.Value.Kind() != constant.String {
+					report.Dynamic = append(report.Dynamic, use)
+					return true
+				}
+				use.Key = constant.StringVal(tv.Value)
+				report.Uses = append(report.Uses, use)
+				if !declared.has(use.Key) {
+					report.Unknown = append(report.Unknown, use)
+				}
+				return true
+			})
+		}
+	}
+	for _, list := range [][]Use{report.Uses, report.Unknown, report.Dynamic} {
+		sortUses(list)
+	}
+	return report, nil
+}
+
+// configMethod returns the name of the Config method call invokes.
+func configMethod(info *types.Info, call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return "", false
+	}
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != configPath {
+		return "", false
+	}
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return "", false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); !ok || named.Obj().Name() != "Config" {
+		return "", false
+	}
+	return fn.Name(), true
+}
+
+type keySet struct {
+	exact    map[string]bool
+	patterns []string
+}
+
+func declaredKeys(opts Options) keySet {
+	set := keySet{exact: make(map[string]bool)}
+	add := func(k string) {
+		if rules.IsPattern(k) {
+			set.patterns = append(set.patterns, k)
+		} else {
+			set.exact[k] = true
+		}
+	}
+	for _, k := range opts.Keys {
+		add(k)
+	}
+	if opts.Config != nil {
+		for _, k := range opts.Config.AllKeys() {
+			add(k)
+		}
+		for _, ck := range opts.Config.ExportContract("").Keys {
+			add(ck.Key)
+		}
+	}
+	return set
+}
+
+func (s keySet) has(key string) bool {
+	if s.exact[key] {
+		return true
+	}
+	for _, p := range s.patterns {
+		if rules.MatchPattern(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortUses(uses []Use) {
+	sort.Slice(uses, func(i, j int) bool {
+		a, b := uses[i].Pos, uses[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+}
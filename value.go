@@ -0,0 +1,124 @@
+package config
+
+import (
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Value Metadata
+// =============================================================================
+
+// SetProvenance is the provenance of keys written with Config.Set.
+const SetProvenance = "set"
+
+// Value is a configuration value with its metadata.
+type Value struct {
+	key      string
+	raw      any
+	source   string
+	secret   bool
+	pii      bool
+	redacted any
+}
+
+// Key returns the dotted key.
+func (v Value) Key() string { return v.key }
+
+// Raw returns the value as loaded.
+func (v Value) Raw() any { return v.raw }
+
+// Source returns the name of the source that supplied the value, e.g.
+// "file:config.yaml", "hook:defaults" or "set".
+func (v Value) Source() string { return v.source }
+
+// IsNull reports whether the value is an explicit null.
+func (v Value) IsNull() bool { return v.raw == nil }
+
+// IsSecret reports whether the key matches a secret pattern.
+func (v Value) IsSecret() bool { return v.secret }
+
+// IsPII reports whether the key is tagged as personal data.
+func (v Value) IsPII() bool { return v.pii }
+
+// Redacted returns the value as it appears in exports: the raw value, or
+// the configured redaction of a secret.
+func (v Value) Redacted() any { return v.redacted }
+
+// IsDefault reports whether the value was filled in by a defaults hook
+// rather than supplied by a source.
+func (v Value) IsDefault() bool { return v.source == "hook:defaults" }
+
+// String converts the value like GetString; null converts to "".
+func (v Value) String() string {
+	if v.raw == nil {
+		return ""
+	}
+	s, _ := asString(v.raw)
+	return s
+}
+
+// Int converts the value like GetInt, reporting whether it converted.
+func (v Value) Int() (int, bool) { return asInt(v.raw) }
+
+// Bool converts the value like GetBool.
+func (v Value) Bool() bool {
+	b, _ := asBool(v.raw)
+	return b
+}
+
+// Float converts the value like GetFloat, reporting whether it converted.
+func (v Value) Float() (float64, bool) { return asFloat(v.raw) }
+
+// Duration converts the value like GetDuration, reporting whether it converted.
+func (v Value) Duration() (time.Duration, bool) { return asDuration(v.raw) }
+
+// StringSlice converts the value like GetStringSlice.
+func (v Value) StringSlice() []string {
+	s, _ := asStringSlice(v.raw)
+	return s
+}
+
+// Lookup returns a key's value and metadata.
+func (c *Config) Lookup(key string) (Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	raw, ok := c.data[key]
+	if !ok {
+		return Value{}, false
+	}
+	c.auditRead(key)
+	return c.valueOf(key, raw), true
+}
+
+// Walk calls fn for every key in sorted order until fn returns false.
+// It iterates over a snapshot, so fn may call back into the Config.
+func (c *Config) Walk(fn func(key string, v Value) bool) {
+	c.mu.RLock()
+	keys := mapKeys(c.data)
+	sort.Strings(keys)
+	values := make([]Value, len(keys))
+	for i, k := range keys {
+		values[i] = c.valueOf(k, c.data[k])
+	}
+	c.mu.RUnlock()
+
+	for i, k := range keys {
+		if !fn(k, values[i]) {
+			return
+		}
+	}
+}
+
+// valueOf builds a Value; the caller must hold c.mu.
+func (c *Config) valueOf(key string, raw any) Value {
+	return Value{
+		key:      key,
+		raw:      raw,
+		source:   c.provenance[key],
+		secret:   c.isSecret(key),
+		pii:      c.isPII(key),
+		redacted: c.redact(key, raw),
+	}
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Helm-style Values
+// =============================================================================
+
+// ValuesSource merges values files and --set style pairs with Helm's
+// precedence: each file overrides the ones before it, pairs override all
+// files. Maps merge recursively, lists and scalars are replaced wholesale,
+// and null removes a key. Merging happens before flattening, so a shorter
+// list in an override file does not leave stale indexes behind.
+type ValuesSource struct {
+	BaseSource
+	files []string
+	pairs []string
+}
+
+// Values creates a source from a base values file and override files.
+func Values(files ...string) *ValuesSource {
+	return ValuesWithPriority(DefaultFilePriority, files...)
+}
+
+func ValuesWithPriority(priority int, files ...string) *ValuesSource {
+	return &ValuesSource{
+		BaseSource: NewBaseSource("values:"+strings.Join(files, ","), priority, files...),
+		files:      files,
+	}
+}
+
+// KVPairs creates a source from --set style pairs only.
+func KVPairs(pairs ...string) *ValuesSource {
+	s := ValuesWithPriority(DefaultKVPriority)
+	s.BaseSource = NewBaseSource("kv:"+strings.Join(pairs, ","), DefaultKVPriority)
+	return s.Set(pairs...)
+}
+
+// Set applies --set style pairs ("a.b=1", "list={x,y}", "a[0].b=x") on top
+// of the files. Several pairs may share one string, separated by commas.
+func (s *ValuesSource) Set(pairs ...string) *ValuesSource {
+	s.pairs = append(s.pairs, pairs...)
+	return s
+}
+
+func (s *ValuesSource) Load() (map[string]any, error) {
+	values := make(map[string]any)
+	for _, file := range s.files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read values: %w", err)
+		}
+		var decoded map[string]any
+		if err := decoderFor(file).Decode(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("decode values %s: %w", file, err)
+		}
+		mergeValues(values, decoded)
+	}
+
+	if err := applyKVPairs(values, s.pairs...); err != nil {
+		return nil, err
+	}
+	return flattenToDot(values), nil
+}
+
+// ParseKVPairs parses --set style pairs into a nested map. Values are typed
+// like Helm's --set: integers, booleans and null are converted, everything
+// else (including floats) stays a string.
+func ParseKVPairs(pairs ...string) (map[string]any, error) {
+	values := make(map[string]any)
+	if err := applyKVPairs(values, pairs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// mergeValues merges src into dst: maps recursively, everything else
+// replaced, nil deleting the key.
+func mergeValues(dst, src map[string]any) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if srcMap, ok := v.(map[string]any); ok {
+			if dstMap, ok := dst[k].(map[string]any); ok {
+				mergeValues(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = deepCloneValue(v)
+	}
+}
+
+// =============================================================================
+// --set Parsing
+// =============================================================================
+
+type valuesPathSegment struct {
+	name    string
+	indexes []int
+}
+
+func applyKVPairs(values map[string]any, pairs ...string) error {
+	for _, arg := range pairs {
+		for _, pair := range splitUnescaped(arg, ',', true) {
+			if pair == "" {
+				continue
+			}
+			keyPart, valuePart, ok := cutUnescaped(pair, '=')
+			if !ok {
+				return fmt.Errorf("invalid pair %q: missing '='", pair)
+			}
+			path, err := parseValuesPath(keyPart)
+			if err != nil {
+				return fmt.Errorf("invalid pair %q: %w", pair, err)
+			}
+			if err := setValuesPath(values, path, parseKVValue(valuePart)); err != nil {
+				return fmt.Errorf("invalid pair %q: %w", pair, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseKVValue converts "{a,b}" to a list and types scalars.
+func parseKVValue(s string) any {
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		items := splitUnescaped(s[1:len(s)-1], ',', false)
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = typedKVValue(unescape(item))
+		}
+		return list
+	}
+	return typedKVValue(unescape(s))
+}
+
+func typedKVValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return s
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return s
+}
+
+// parseValuesPath parses "a.b[0].c" into segments.
+func parseValuesPath(key string) ([]valuesPathSegment, error) {
+	var path []valuesPathSegment
+	for _, part := range splitUnescaped(key, '.', false) {
+		name := part
+		var indexes []int
+		for strings.HasSuffix(name, "]") {
+			open := strings.LastIndex(name, "[")
+			if open < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in %q", key)
+			}
+			i, err := strconv.Atoi(name[open+1 : len(name)-1])
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid index in %q", key)
+			}
+			indexes = append([]int{i}, indexes...)
+			name = name[:open]
+		}
+		name = unescape(name)
+		if name == "" {
+			return nil, fmt.Errorf("empty key segment in %q", key)
+		}
+		path = append(path, valuesPathSegment{name: name, indexes: indexes})
+	}
+	return path, nil
+}
+
+func setValuesPath(values map[string]any, path []valuesPathSegment, value any) error {
+	seg, rest := path[0], path[1:]
+
+	if len(seg.indexes) == 0 {
+		if len(rest) == 0 {
+			if value == nil {
+				delete(values, seg.name)
+			} else {
+				values[seg.name] = value
+			}
+			return nil
+		}
+		child, ok := values[seg.name].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			values[seg.name] = child
+		}
+		return setValuesPath(child, rest, value)
+	}
+
+	list, _ := values[seg.name].([]any)
+	updated, err := setValuesIndex(list, seg.indexes, rest, value)
+	if err != nil {
+		return err
+	}
+	values[seg.name] = updated
+	return nil
+}
+
+func setValuesIndex(list []any, indexes []int, rest []valuesPathSegment, value any) ([]any, error) {
+	i := indexes[0]
+	if i > 65536 {
+		return nil, fmt.Errorf("index %d too large", i)
+	}
+	for len(list) <= i {
+		list = append(list, nil)
+	}
+
+	switch {
+	case len(indexes) > 1:
+		inner, _ := list[i].([]any)
+		updated, err := setValuesIndex(inner, indexes[1:], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = updated
+	case len(rest) > 0:
+		child, ok := list[i].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			list[i] = child
+		}
+		if err := setValuesPath(child, rest, value); err != nil {
+			return nil, err
+		}
+	default:
+		list[i] = value
+	}
+	return list, nil
+}
+
+// splitUnescaped splits s on sep, ignoring escaped separators and, when
+// braces is set, separators inside {...}. Escapes are kept.
+func splitUnescaped(s string, sep byte, braces bool) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case braces && c == '{':
+			depth++
+		case braces && c == '}' && depth > 0:
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// cutUnescaped cuts s around the first unescaped sep.
+func cutUnescaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// WASM Hooks
+// =============================================================================
+
+// WASMLimits bound a single module invocation. Timeout and MaxOutput are
+// enforced by the hook; MemoryPages is passed to the runtime.
+type WASMLimits struct {
+	MemoryPages uint32        // 64 KiB pages, defaults to 256 (16 MiB)
+	Timeout     time.Duration // defaults to 1s
+	MaxOutput   int           // bytes, defaults to 1 MiB
+}
+
+// WASMRuntime runs an exported function of a WASM module in a sandbox. The
+// function receives input as JSON and returns JSON. Adapt your runtime (for
+// example github.com/tetratelabs/wazero) to this interface; it must cap
+// memory at limits.MemoryPages, grant no host access beyond what the module
+// needs, and abort when ctx is done. Until a call that ignored the timeout
+// returns, the hook fails loads with ErrStillRunning.
+type WASMRuntime interface {
+	Run(ctx context.Context, module []byte, function string, input []byte, limits WASMLimits) ([]byte, error)
+}
+
+// WASMRuntimeFunc adapts a function to WASMRuntime.
+type WASMRuntimeFunc func(ctx context.Context, module []byte, function string, input []byte, limits WASMLimits) ([]byte, error)
+
+func (f WASMRuntimeFunc) Run(ctx context.Context, module []byte, function string, input []byte, limits WASMLimits) ([]byte, error) {
+	return f(ctx, module, function, input, limits)
+}
+
+// WASMHookOptions configure a WASM hook.
+type WASMHookOptions struct {
+	Name     string // defaults to the module file name, or "wasm"
+	Function string // exported function, defaults to "transform"
+	Priority int    // hook priority, defaults to 40
+
+	// Validate treats the module as a validator: returned data is ignored
+	// and only its errors are applied.
+	Validate bool
+
+	Limits WASMLimits
+}
+
+// WASMHook runs tenant-provided transformation or validation logic after
+// loading. The module receives {"data": {...}} with the flat key map and
+// answers {"data": {...}, "errors": [...]}: returned data replaces the
+// loaded data, and any errors fail the load.
+type WASMHook struct {
+	runtime WASMRuntime
+	module  []byte
+	opts    WASMHookOptions
+	calls   boundedCalls
+}
+
+// WASMError reports the errors a module returned.
+type WASMError struct {
+	Hook   string
+	Errors []string
+}
+
+func (e *WASMError) Error() string {
+	return fmt.Sprintf("wasm hook %s: %s", e.Hook, strings.Join(e.Errors, "; "))
+}
+
+// ErrWASMOutputTooLarge is returned when a module exceeds Limits.MaxOutput.
+var ErrWASMOutputTooLarge = errors.New("wasm output exceeds limit")
+
+func NewWASMHook(runtime WASMRuntime, module []byte, opts WASMHookOptions) *WASMHook {
+	if opts.Name == "" {
+		opts.Name = "wasm"
+	}
+	if opts.Function == "" {
+		opts.Function = "transform"
+	}
+	if opts.Priority == 0 {
+		opts.Priority = 40
+	}
+	if opts.Limits.MemoryPages == 0 {
+		opts.Limits.MemoryPages = 256
+	}
+	if opts.Limits.Timeout <= 0 {
+		opts.Limits.Timeout = time.Second
+	}
+	if opts.Limits.MaxOutput <= 0 {
+		opts.Limits.MaxOutput = 1 << 20
+	}
+	return &WASMHook{runtime: runtime, module: module, opts: opts}
+}
+
+// LoadWASMHook reads a module from disk.
+func LoadWASMHook(runtime WASMRuntime, path string, opts WASMHookOptions) (*WASMHook, error) {
+	module, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wasm module: %w", err)
+	}
+	if opts.Name == "" {
+		opts.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return NewWASMHook(runtime, module, opts), nil
+}
+
+func (h *WASMHook) Name() string  { return h.opts.Name }
+func (h *WASMHook) Priority() int { return h.opts.Priority }
+
+func (h *WASMHook) OnPostLoad(_ *Config, data map[string]any) error {
+	input, err := json.Marshal(map[string]any{"data": data})
+	if err != nil {
+		return fmt.Errorf("encode wasm input: %w", err)
+	}
+
+	output, err := h.run(input)
+	if err != nil {
+		return err
+	}
+	if len(output) > h.opts.Limits.MaxOutput {
+		return ErrWASMOutputTooLarge
+	}
+
+	var result struct {
+		Data   map[string]any `json:"data"`
+		Errors []string       `json:"errors"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return fmt.Errorf("decode wasm output: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return &WASMError{Hook: h.opts.Name, Errors: result.Errors}
+	}
+	if !h.opts.Validate && result.Data != nil {
+		clear(data)
+		maps.Copy(data, flattenToDot(result.Data))
+	}
+	return nil
+}
+
+// run invokes the module, returning once the timeout expires even if the
+// runtime does not honour cancellation.
+func (h *WASMHook) run(input []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.opts.Limits.Timeout)
+	defer cancel()
+	out, err := runBounded(&h.calls, ctx, func(ctx context.Context) ([]byte, error) {
+		return h.runtime.Run(ctx, h.module, h.opts.Function, input, h.opts.Limits)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wasm %s: %w", h.opts.Function, err)
+	}
+	return out, nil
+}
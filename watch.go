@@ -0,0 +1,516 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/os-golib/go-config/source"
+)
+
+// =============================================================================
+// File Change Tracking
+// =============================================================================
+
+// statFunc returns a path's modification time and whether it exists.
+type statFunc func(path string) (time.Time, bool)
+
+func osStat(path string) (time.Time, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// modTracker remembers modification times of watched files and glob
+// matches between polls.
+type modTracker struct {
+	modTimes map[string]time.Time
+	globs    map[string]map[string]time.Time // pattern -> match -> mod time
+}
+
+func newModTracker(targets []WatchTarget, stat statFunc) *modTracker {
+	t := &modTracker{
+		modTimes: make(map[string]time.Time),
+		globs:    make(map[string]map[string]time.Time),
+	}
+	for _, target := range targets {
+		switch target.Kind {
+		case WatchTargetFile:
+			mt, _ := stat(target.Path)
+			t.modTimes[target.Path] = mt
+		case WatchTargetGlob:
+			matches := make(map[string]time.Time)
+			files, _ := filepath.Glob(target.Path)
+			for _, f := range files {
+				matches[f], _ = stat(f)
+			}
+			t.globs[target.Path] = matches
+		}
+	}
+	return t
+}
+
+// changed records the current modification times and reports whether any
+// file was modified (or created) since the previous poll. For globs, added
+// and removed matches count as changes too.
+func (t *modTracker) changed(stat statFunc) bool {
+	changed := false
+	for path, old := range t.modTimes {
+		mt, ok := stat(path)
+		if ok && mt.After(old) {
+			t.modTimes[path] = mt
+			changed = true
+		}
+	}
+	for pattern, matches := range t.globs {
+		files, _ := filepath.Glob(pattern)
+		current := make(map[string]time.Time, len(files))
+		for _, f := range files {
+			mt, _ := stat(f)
+			current[f] = mt
+			if old, seen := matches[f]; !seen || mt.After(old) {
+				changed = true
+			}
+		}
+		if len(current) != len(matches) {
+			changed = true // a match was removed
+		}
+		t.globs[pattern] = current
+	}
+	return changed
+}
+
+// =============================================================================
+// Watch Target Registry
+// =============================================================================
+
+// Watch target kinds.
+const (
+	WatchTargetFile = source.TargetFile
+	WatchTargetGlob = source.TargetGlob
+	WatchTargetPoll = source.TargetPoll
+)
+
+// SourceTarget is a watch target as described by a single source.
+type SourceTarget = source.Target
+
+// Targeter is implemented by sources that describe their watch targets
+// directly instead of through WatchPaths.
+type Targeter = source.Targeter
+
+// WatchTarget is an entry in a config's watch-target registry.
+type WatchTarget struct {
+	Kind    string   // WatchTargetFile, WatchTargetGlob or WatchTargetPoll
+	Path    string   // file path or glob pattern; empty for pollers
+	Sources []string // names of the sources referencing the target
+}
+
+// WatchTargets returns the deduplicated set of files, globs and pollers that
+// Watch follows, collected from every source including those nested in
+// middleware and composites. A file referenced by several sources appears
+// once, listing each of them.
+func (c *Config) WatchTargets() []WatchTarget {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var targets []WatchTarget
+	index := make(map[SourceTarget]int)
+	for _, src := range c.sources {
+		walkSources(src, func(s Source) {
+			for _, t := range source.Targets(s) {
+				if t.Kind == WatchTargetPoll {
+					// Each poller is its own target.
+					targets = append(targets, WatchTarget{Kind: t.Kind, Sources: []string{s.Name()}})
+					continue
+				}
+				if i, ok := index[t]; ok {
+					if !slices.Contains(targets[i].Sources, s.Name()) {
+						targets[i].Sources = append(targets[i].Sources, s.Name())
+					}
+					continue
+				}
+				index[t] = len(targets)
+				targets = append(targets, WatchTarget{Kind: t.Kind, Path: t.Path, Sources: []string{s.Name()}})
+			}
+		})
+	}
+	return targets
+}
+
+// =============================================================================
+// Watch Intervals
+// =============================================================================
+
+// Bounds for watch intervals. Shorter intervals turn watching into a busy
+// loop; longer ones are almost certainly a unit mistake.
+var (
+	MinWatchInterval = 100 * time.Millisecond
+	MaxWatchInterval = 24 * time.Hour
+)
+
+// ErrInvalidWatchInterval is returned for intervals outside
+// [MinWatchInterval, MaxWatchInterval].
+var ErrInvalidWatchInterval = errors.New("invalid watch interval")
+
+func validateWatchInterval(d time.Duration) error {
+	if d < MinWatchInterval || d > MaxWatchInterval {
+		return fmt.Errorf("%w: %s is outside [%s, %s]", ErrInvalidWatchInterval, d, MinWatchInterval, MaxWatchInterval)
+	}
+	return nil
+}
+
+// WatchIntervaler is implemented by sources that should be polled less
+// often than the watch interval, such as remote backends with rate limits.
+type WatchIntervaler interface {
+	WatchInterval() time.Duration
+}
+
+// SetWatchInterval polls the named source at most every d while watching,
+// overriding its WatchInterval. It applies to sources that poll (remote
+// sources); files are checked on every tick, as a stat is cheap. Intervals
+// shorter than the watch interval have no effect.
+func (c *Config) SetWatchInterval(source string, d time.Duration) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watchIntervals == nil {
+		c.watchIntervals = make(map[string]time.Duration)
+	}
+	c.watchIntervals[source] = d
+	return c
+}
+
+// watchPoller is a Poller with its own polling interval.
+type watchPoller struct {
+	Poller
+	name  string
+	every time.Duration // zero polls on every tick
+	next  time.Time
+}
+
+// due reports whether the poller should run at now, scheduling the next run.
+func (p *watchPoller) due(now time.Time) bool {
+	if now.Before(p.next) {
+		return false
+	}
+	p.next = now.Add(p.every)
+	return true
+}
+
+// collectPollers returns every Poller among the sources, including sources
+// nested in middleware and composites, with its configured interval.
+func (c *Config) collectPollers() ([]*watchPoller, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var pollers []*watchPoller
+	var errs []error
+	for _, src := range c.sources {
+		if c.offline && isRemote(src) {
+			continue
+		}
+		walkSources(src, func(s Source) {
+			p, ok := s.(Poller)
+			if !ok {
+				return
+			}
+			wp := &watchPoller{Poller: p, name: s.Name()}
+			if d, ok := c.watchIntervals[s.Name()]; ok {
+				wp.every = d
+			} else if wi, ok := s.(WatchIntervaler); ok {
+				wp.every = wi.WatchInterval()
+			}
+			if wp.every != 0 {
+				if err := validateWatchInterval(wp.every); err != nil {
+					errs = append(errs, fmt.Errorf("source %s: %w", s.Name(), err))
+				}
+			}
+			pollers = append(pollers, wp)
+		})
+	}
+	return pollers, errors.Join(errs...)
+}
+
+// watchState tracks everything a config watches: file paths and pollers.
+type watchState struct {
+	files   *modTracker
+	pollers []*watchPoller
+}
+
+func (c *Config) newWatchState() (*watchState, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("config is closed")
+	}
+	targets := c.WatchTargets()
+	pollers, err := c.collectPollers()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no watchable sources configured")
+	}
+	return &watchState{files: newModTracker(targets, osStat), pollers: pollers}, nil
+}
+
+// changed reports whether any file or due poller reports a change. Poll
+// errors go to the config's error handler.
+func (w *watchState) changed(c *Config, stat statFunc) bool {
+	changed := w.files.changed(stat)
+	now := time.Now()
+	for _, p := range w.pollers {
+		if !p.due(now) {
+			continue
+		}
+		ok, err := p.Poll()
+		c.handleError(err)
+		changed = changed || ok
+	}
+	return changed
+}
+
+func (c *Config) watchLoop(interval time.Duration, state *watchState) {
+	defer c.watchers.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.events.emit(Event{Type: EventWatcherStopped, Err: c.ctx.Err()})
+			return
+		case <-ticker.C:
+			if state.changed(c, osStat) {
+				c.handleError(c.Load())
+			}
+		}
+	}
+}
+
+// WatchTrigger runs watch checks on demand instead of on a ticker, so tests
+// can drive reloads deterministically.
+type WatchTrigger struct {
+	config *Config
+	mu     sync.Mutex
+	state  *watchState
+}
+
+// ManualWatch prepares watching like Watch but starts no goroutine: each
+// call to the returned trigger's Tick checks the watched files and pollers
+// once.
+func (c *Config) ManualWatch() (*WatchTrigger, error) {
+	state, err := c.newWatchState()
+	if err != nil {
+		return nil, err
+	}
+	return &WatchTrigger{config: c, state: state}, nil
+}
+
+// Tick runs one watch check and, if anything changed, reloads and waits
+// for observers to be notified before returning (change sets held back by
+// coalescing are delivered later). It returns the reload error rather than
+// passing it to the error handler; poll errors go to the handler as when
+// watching. Once the config is closed, Tick does nothing.
+func (t *WatchTrigger) Tick() (reloaded bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.config.ctx.Err() != nil || !t.state.changed(t.config, osStat) {
+		return false, nil
+	}
+	err = t.config.Load()
+	t.config.delivery.wait()
+	return true, err
+}
+
+// =============================================================================
+// Watch Group
+// =============================================================================
+
+// WatchGroup watches many Config instances (e.g. per-tenant configs) with one
+// shared ticker and a bounded worker pool instead of a goroutine and ticker
+// per config. Each watched path is stat'ed once per tick, however many
+// configs reference it.
+type WatchGroup struct {
+	interval time.Duration
+	workers  int
+
+	mu       sync.Mutex
+	members  map[*Config]*watchState
+	inflight map[*Config]bool
+	jobs     chan *Config
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	started  bool
+}
+
+// NewWatchGroup creates a group polling every interval and reloading with at
+// most workers concurrent loads. Like Watch, it returns an error wrapping
+// ErrInvalidWatchInterval for intervals outside
+// [MinWatchInterval, MaxWatchInterval].
+func NewWatchGroup(interval time.Duration, workers int) (*WatchGroup, error) {
+	if err := validateWatchInterval(interval); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WatchGroup{
+		interval: interval,
+		workers:  workers,
+		members:  make(map[*Config]*watchState),
+		inflight: make(map[*Config]bool),
+		jobs:     make(chan *Config, workers),
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// Add registers a config with the group. Closing the config removes it.
+func (g *WatchGroup) Add(c *Config) error {
+	state, err := c.newWatchState()
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members[c] = state
+	return nil
+}
+
+// Remove stops watching a config.
+func (g *WatchGroup) Remove(c *Config) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.members, c)
+}
+
+// Len returns the number of watched configs.
+func (g *WatchGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.members)
+}
+
+// Start launches the shared ticker and worker pool. It is a no-op if the
+// group is already running.
+func (g *WatchGroup) Start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		return
+	}
+	g.started = true
+
+	for i := 0; i < g.workers; i++ {
+		g.wg.Add(1)
+		go g.worker()
+	}
+	g.wg.Add(1)
+	go g.loop()
+}
+
+// Close stops the group and waits for in-flight reloads to finish.
+func (g *WatchGroup) Close() error {
+	g.cancel()
+	g.wg.Wait()
+	return nil
+}
+
+func (g *WatchGroup) loop() {
+	defer g.wg.Done()
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, c := range g.poll() {
+				select {
+				case g.jobs <- c:
+				case <-g.ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// poll checks every member once and returns the configs needing a reload.
+// Members are checked without holding g.mu, since pollers may call remote
+// backends.
+func (g *WatchGroup) poll() []*Config {
+	type member struct {
+		config *Config
+		state  *watchState
+	}
+	g.mu.Lock()
+	members := make([]member, 0, len(g.members))
+	for c, state := range g.members {
+		if c.ctx.Err() != nil {
+			delete(g.members, c)
+			continue
+		}
+		if g.inflight[c] {
+			continue // re-checked on the next tick once the reload finishes
+		}
+		members = append(members, member{c, state})
+	}
+	g.mu.Unlock()
+
+	stats := make(map[string]time.Time)
+	missing := make(map[string]bool)
+	stat := func(path string) (time.Time, bool) {
+		if mt, ok := stats[path]; ok {
+			return mt, true
+		}
+		if missing[path] {
+			return time.Time{}, false
+		}
+		mt, ok := osStat(path)
+		if ok {
+			stats[path] = mt
+		} else {
+			missing[path] = true
+		}
+		return mt, ok
+	}
+
+	var due []*Config
+	for _, m := range members {
+		if !m.state.changed(m.config, stat) {
+			continue
+		}
+		g.mu.Lock()
+		if _, ok := g.members[m.config]; ok {
+			g.inflight[m.config] = true
+			due = append(due, m.config)
+		}
+		g.mu.Unlock()
+	}
+	return due
+}
+
+func (g *WatchGroup) worker() {
+	defer g.wg.Done()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case c := <-g.jobs:
+			c.handleError(c.Load())
+			g.mu.Lock()
+			delete(g.inflight, c)
+			g.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Webhook Notifier
+// =============================================================================
+
+// DefaultWebhookRetries is the number of retries when
+// WebhookOptions.MaxRetries is nil.
+const DefaultWebhookRetries = 3
+
+// WebhookOptions configures a WebhookNotifier.
+type WebhookOptions struct {
+	URL        string        // endpoint receiving POSTed change sets
+	Service    string        // service name included in the payload
+	Secret     []byte        // HMAC-SHA256 key; signature sent in X-Config-Signature
+	Slack      bool          // send a Slack-compatible {"text": ...} payload
+	MaxRetries *int          // retries after the first attempt; nil for DefaultWebhookRetries, 0 for none
+	Backoff    time.Duration // initial retry delay, doubled per attempt (default 1s)
+	Auth       AuthProvider  // authenticates requests to the endpoint
+	Client     *http.Client  // defaults to a client with a 10s timeout
+}
+
+// WebhookPayload is the JSON body posted for each change set.
+type WebhookPayload struct {
+	Service   string         `json:"service,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	Changed   map[string]any `json:"changed"`
+	Previous  map[string]any `json:"previous,omitempty"`
+}
+
+// WebhookNotifier is a ChangeSetObserver that POSTs redacted change sets to
+// a webhook (or Slack incoming webhook), retrying with backoff. Secrets are
+// redacted and PII keys left out. Requests are sent asynchronously so slow
+// endpoints never delay other observers; final failures go to the config's
+// error handler. Closing the config stops retries and waits for requests
+// in flight.
+type WebhookNotifier struct {
+	config  *Config
+	opts    WebhookOptions
+	retries int
+}
+
+// NewWebhookNotifier creates a notifier for c; register it with
+// c.ObserveChanges or Builder.AddWebhook.
+func NewWebhookNotifier(c *Config, opts WebhookOptions) *WebhookNotifier {
+	retries := DefaultWebhookRetries
+	if opts.MaxRetries != nil {
+		retries = max(*opts.MaxRetries, 0)
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = defaultHTTPClient(10 * time.Second)
+	}
+	if opts.Auth != nil {
+		opts.Client = AuthClient(opts.Client, opts.Auth)
+	}
+	return &WebhookNotifier{config: c, opts: opts, retries: retries}
+}
+
+// OnChangeSet posts the change set, unless the config is offline or closed
+// or only PII keys changed.
+func (n *WebhookNotifier) OnChangeSet(cs *ChangeSet) {
+	c := n.config
+	if c.Offline() || c.ctx.Err() != nil {
+		return
+	}
+	payload := WebhookPayload{
+		Service:   n.opts.Service,
+		Timestamp: cs.At,
+		Changed:   n.outgoing(cs.Changed),
+		Previous:  n.outgoing(cs.Previous),
+	}
+	if len(payload.Changed) == 0 {
+		return
+	}
+
+	body, err := n.encode(payload)
+	if err != nil {
+		c.handleError(fmt.Errorf("webhook: %w", err))
+		return
+	}
+	c.watchers.Add(1)
+	go func() {
+		defer c.watchers.Done()
+		c.handleError(n.send(c.ctx, body))
+	}()
+}
+
+// outgoing returns data with secrets redacted and PII keys removed.
+func (n *WebhookNotifier) outgoing(data map[string]any) map[string]any {
+	out := n.config.redactMap(data)
+	n.config.mu.RLock()
+	defer n.config.mu.RUnlock()
+	for k := range out {
+		if n.config.isPII(k) {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+func (n *WebhookNotifier) encode(p WebhookPayload) ([]byte, error) {
+	if !n.opts.Slack {
+		return json.Marshal(p)
+	}
+
+	keys := mapKeys(p.Changed)
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys)+1)
+	title := "Configuration changed"
+	if p.Service != "" {
+		title += " in " + p.Service
+	}
+	lines = append(lines, "*"+title+"*")
+	for _, k := range keys {
+		if old, ok := p.Previous[k]; ok {
+			lines = append(lines, fmt.Sprintf("• `%s`: %v → %v", k, old, p.Changed[k]))
+		} else {
+			lines = append(lines, fmt.Sprintf("• `%s`: %v", k, p.Changed[k]))
+		}
+	}
+	return json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+// send posts body, retrying with backoff. It gives up silently once ctx is
+// done.
+func (n *WebhookNotifier) send(ctx context.Context, body []byte) error {
+	var lastErr error
+	backoff := n.opts.Backoff
+	for attempt := 0; attempt <= n.retries; attempt++ {
+		if attempt > 0 {
+			wait := time.NewTimer(backoff)
+			select {
+			case <-wait.C:
+			case <-ctx.Done():
+				wait.Stop()
+				return nil
+			}
+			backoff *= 2
+		}
+		if lastErr = n.post(ctx, body); lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	if n.retries == 0 {
+		return fmt.Errorf("webhook %s: %w", n.opts.URL, lastErr)
+	}
+	return fmt.Errorf("webhook %s: failed after %d attempts: %w", n.opts.URL, n.retries+1, lastErr)
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.opts.Secret) > 0 {
+		mac := hmac.New(sha256.New, n.opts.Secret)
+		mac.Write(body)
+		req.Header.Set("X-Config-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}